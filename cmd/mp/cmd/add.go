@@ -12,11 +12,36 @@ import (
 	"golang.org/x/term"
 )
 
+var (
+	addUsernameFlag string
+	addURLsFlag     []string
+	addNotesFlag    string
+	addFieldsFlag   []string
+	addFolderFlag   string
+	addTagsFlag     []string
+	addOTPFlag      string
+	addGenerateFlag bool
+	addPolicyFlag   string
+)
+
 var addCmd = &cobra.Command{
 	Use:   "add",
-	Short: "",
-	Long:  "",
+	Short: "Add an entry",
+	Long: "Add an entry, the title and the password are asked interactively. " +
+		"Without --username, --url and --notes they are asked too, empty input leaves them empty",
 	Run: func(cmd *cobra.Command, args []string) {
+		fields, err := parseFields(addFieldsFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		otpSecret, err := parseOTP(addOTPFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		store, err := provide()
 		if err != nil {
 			fmt.Println(err)
@@ -51,14 +76,32 @@ var addCmd = &cobra.Command{
 		fmt.Printf("title set: %s\n", title)
 
 		var password string
-		fmt.Printf("Set password for title %s:", title)
-		pass, err := term.ReadPassword(int(os.Stdin.Fd()))
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+		if addGenerateFlag {
+			if password, err = generatePassword(addPolicyFlag); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Password for title %s was generated\n", title)
+		} else {
+			fmt.Printf("Set password for title %s:", title)
+			pass, err := term.ReadPassword(int(os.Stdin.Fd()))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			password = string(pass)
+			fmt.Println()
+		}
+
+		username, urls, notes := addUsernameFlag, addURLsFlag, addNotesFlag
+		if !cmd.Flags().Changed("username") && !cmd.Flags().Changed("url") && !cmd.Flags().Changed("notes") {
+			username = readLine(scanner, "Set a username (optional): ")
+			urls = splitList(readLine(scanner, "Set URLs, comma separated (optional): "))
+			notes = readLine(scanner, "Set notes (optional): ")
 		}
 
-		password = string(pass)
 		id := uuid.New().String()
 		if err := store.Add(manager.Entry{
 			ID:        id,
@@ -66,6 +109,13 @@ var addCmd = &cobra.Command{
 			Password:  password,
 			CreatedAt: time.Now().UTC(),
 			UpdatedAt: time.Now().UTC(),
+			Username:  username,
+			URLs:      urls,
+			Notes:     notes,
+			Fields:    fields,
+			Folder:    manager.CleanFolder(addFolderFlag),
+			Tags:      manager.CleanTags(addTagsFlag),
+			OTP:       otpSecret,
 		}); err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -77,6 +127,31 @@ var addCmd = &cobra.Command{
 	},
 }
 
+// readLine prints the prompt and reads a line from the scanner.
+func readLine(scanner *bufio.Scanner, prompt string) string {
+	fmt.Print(prompt)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return ""
+	}
+
+	return scanner.Text()
+}
+
 func init() {
+	addCmd.Flags().StringVarP(&addUsernameFlag, "username", "u", "", "username")
+	addCmd.Flags().StringSliceVar(&addURLsFlag, "url", nil, "url, can be repeated or comma separated")
+	addCmd.Flags().StringVar(&addNotesFlag, "notes", "", "free-form notes")
+	addCmd.Flags().StringArrayVar(&addFieldsFlag, "field", nil,
+		"custom field as [type:]name=value, the type is text, hidden, url or email, can be repeated")
+	addCmd.Flags().StringVar(&addFolderFlag, "folder", "", "folder path such as work/dev")
+	addCmd.Flags().StringSliceVar(&addTagsFlag, "tag", nil, "tag, can be repeated or comma separated")
+	addCmd.Flags().StringVar(&addOTPFlag, "otp", "", "TOTP otpauth:// URI or base32 seed, it is kept in the shell history")
+	addCmd.Flags().BoolVarP(&addGenerateFlag, "generate", "g", false, "generate the password instead of asking it")
+	addCmd.Flags().StringVar(&addPolicyFlag, "policy", "", "policy of the generated password, generate.policy by default")
 	rootCmd.AddCommand(addCmd)
 }
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/polylab/mypass-cli/internal/attach"
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	attachNameFlag   string
+	attachOutputFlag string
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Manage encrypted file attachments of entries",
+	Long: "Manage encrypted file attachments of entries. Attachments are stored encrypted in the attachments " +
+		"directory next to the vault file, a content is stored once however many entries it is attached to",
+}
+
+var attachAddCmd = &cobra.Command{
+	Use:   "add <entry> <file>",
+	Short: "Attach a file to an entry",
+	Long:  "Attach a file to an entry selected by reference, the attachment is named as the file unless --name is set",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := attachNameFlag
+		if name == "" {
+			name = filepath.Base(args[1])
+		}
+
+		info, err := os.Stat(args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if maxSize := viper.GetInt64("attach.max_size"); info.Size() > maxSize {
+			fmt.Printf("%s is %d bytes, the limit is %d: %v\n", args[1], info.Size(), maxSize, attach.ErrTooLarge)
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		store, entry := provideEntry(args[0])
+		attachment, err := newAttacher(store).Add(entry.ID, name, data)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Attached %s (%d bytes) to %s\n", attachment.Name, attachment.Size, entry.Path())
+	},
+}
+
+var attachListCmd = &cobra.Command{
+	Use:   "list <entry>",
+	Short: "List attachments of an entry",
+	Long:  "",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, entry := provideEntry(args[0])
+		if len(entry.Attachments) == 0 {
+			fmt.Println("No attachments")
+			return
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSIZE\tSHA256")
+		for _, attachment := range entry.Attachments {
+			fmt.Fprintf(tw, "%s\t%d\t%x\n", attachment.Name, attachment.Size, attachment.Hash)
+		}
+
+		tw.Flush()
+	},
+}
+
+var attachExtractCmd = &cobra.Command{
+	Use:   "extract <entry> <name>",
+	Short: "Extract an attachment of an entry",
+	Long:  "Extract an attachment of an entry to the file named as the attachment or set by --output, - is stdout",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, entry := provideEntry(args[0])
+		attachment, ok := entry.Attachment(args[1])
+		if !ok {
+			fmt.Printf("attachment %s: %v\n", args[1], manager.ErrNotFound)
+			os.Exit(1)
+		}
+
+		data, err := newAttacher(store).Extract(attachment)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		output := attachOutputFlag
+		if output == "" {
+			output = filepath.Base(attachment.Name)
+		}
+
+		if output == "-" {
+			if _, err = os.Stdout.Write(data); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			return
+		}
+
+		if err = writeNewFile(output, data); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Extracted %s to %s\n", attachment.Name, output)
+	},
+}
+
+var attachRemoveCmd = &cobra.Command{
+	Use:   "remove <entry> <name>",
+	Short: "Remove an attachment from an entry",
+	Long: "Remove an attachment from an entry, the encrypted content is kept for the history of the entry " +
+		"until the tx log is compacted",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, entry := provideEntry(args[0])
+		if err := store.Detach(entry.ID, args[1]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Removed %s from %s\n", args[1], entry.Path())
+		pruneAttachments(store)
+	},
+}
+
+var attachPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete encrypted contents no entry references",
+	Long: "Delete the encrypted contents in the attachments directory that no tx of the log references. " +
+		"Remove and compact prune on their own, prune cleans up after an automatic compaction. " +
+		"Attachments of a restored backup may reference pruned contents",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if pruneAttachments(store) == 0 {
+			fmt.Println("No unreferenced attachment contents")
+		}
+	},
+}
+
+// pruneAttachments deletes the blobs the log no longer references and returns their number, it exits on errors.
+func pruneAttachments(store *manager.Store) int {
+	removed, err := newAttacher(store).Prune()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if removed > 0 {
+		fmt.Printf("%d unreferenced attachment contents were deleted\n", removed)
+	}
+
+	return removed
+}
+
+// provideEntry provides the store and the entry referenced by ref, it exits on errors.
+func provideEntry(ref string) (*manager.Store, manager.Entry) {
+	store, err := provide()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	entry, err := selectEntry(store, []string{ref}, nil, nil, nil)
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+
+	return store, entry
+}
+
+func newAttacher(store *manager.Store) *attach.Attacher {
+	return attach.New(store, attach.Dir(storageFileFlag), attach.WithMaxSize(viper.GetInt64("attach.max_size")))
+}
+
+// writeNewFile writes data to a new file readable by the owner only, an existing file is not overwritten.
+func writeNewFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	attachAddCmd.Flags().StringVar(&attachNameFlag, "name", "", "attachment name, the file name by default")
+	attachExtractCmd.Flags().StringVarP(&attachOutputFlag, "output", "o", "", "output file, - is stdout")
+	attachCmd.AddCommand(attachAddCmd, attachListCmd, attachExtractCmd, attachRemoveCmd, attachPruneCmd)
+	rootCmd.AddCommand(attachCmd)
+}
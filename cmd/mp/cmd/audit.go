@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/audit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// auditFindingsCode is the exit code of an audit with findings, errors exit with 1.
+const auditFindingsCode = 2
+
+var (
+	auditJSONFlag     bool
+	auditMinScoreFlag int
+	auditMaxAgeFlag   string
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit passwords",
+	Long: "Report weak passwords by an estimate of the guesses to crack them, passwords reused by several entries " +
+		"and passwords of entries not updated for longer than the max age. " +
+		"Exits with 2 when anything is found and with 1 on errors",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		minScore, maxAge := viper.GetInt("audit.min_score"), viper.GetString("audit.max_age")
+		if cmd.Flags().Changed("min-score") {
+			minScore = auditMinScoreFlag
+		}
+
+		if cmd.Flags().Changed("max-age") {
+			maxAge = auditMaxAgeFlag
+		}
+
+		opts, err := auditOptions(minScore, maxAge)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		report, err := audit.Audit(store.List(), opts...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if auditJSONFlag {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println(string(data))
+		} else {
+			printReport(report)
+		}
+
+		if report.Findings() > 0 {
+			os.Exit(auditFindingsCode)
+		}
+	},
+}
+
+func auditOptions(minScore int, maxAge string) ([]audit.Option, error) {
+	if minScore < 0 || minScore > 4 {
+		return nil, fmt.Errorf("min score %d is not from 0 to 4", minScore)
+	}
+
+	opts := []audit.Option{audit.WithMinScore(minScore), audit.WithMaxAge(0)}
+	if maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return nil, fmt.Errorf("max age: %w", err)
+		}
+		opts = append(opts, audit.WithMaxAge(d))
+	}
+
+	return opts, nil
+}
+
+func printReport(report audit.Report) {
+	if len(report.Weak) > 0 {
+		fmt.Println("Weak passwords:")
+		for _, weak := range report.Weak {
+			fmt.Printf("  %s (%s): score %d of 4, %.0f bits", weak.Path, weak.ID, weak.Score, weak.Entropy)
+			if len(weak.Patterns) > 0 {
+				fmt.Printf(", %s", strings.Join(weak.Patterns, ", "))
+			}
+			fmt.Println()
+		}
+	}
+
+	if len(report.Reused) > 0 {
+		fmt.Println("Reused passwords:")
+		for _, reused := range report.Reused {
+			refs := make([]string, len(reused.Entries))
+			for idx, ref := range reused.Entries {
+				refs[idx] = fmt.Sprintf("%s (%s)", ref.Path, ref.ID)
+			}
+			fmt.Printf("  %s\n", strings.Join(refs, ", "))
+		}
+	}
+
+	if len(report.Stale) > 0 {
+		fmt.Println("Stale passwords:")
+		for _, stale := range report.Stale {
+			fmt.Printf("  %s (%s): updated %s, %d days ago\n",
+				stale.Path, stale.ID, stale.UpdatedAt.Format("02 Jan 06"), stale.AgeDays)
+		}
+	}
+
+	fmt.Printf("Checked %d passwords: %d weak, %d reused, %d stale\n",
+		report.Checked, len(report.Weak), len(report.Reused), len(report.Stale))
+}
+
+func init() {
+	auditCmd.Flags().BoolVar(&auditJSONFlag, "json", false, "print the report as JSON")
+	auditCmd.Flags().IntVar(&auditMinScoreFlag, "min-score", 0, "lowest score from 0 to 4 of a password that is not weak, 0 disables the check (default from settings)")
+	auditCmd.Flags().StringVar(&auditMaxAgeFlag, "max-age", "", "age of a stale password, e.g. 8760h, empty disables the check (default from settings)")
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/setup"
+	"github.com/polylab/mypass-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage vault backups",
+	Long:  "",
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List vault backups",
+	Long:  "",
+	Run: func(cmd *cobra.Command, args []string) {
+		backups, err := store.ListBackups(storageFileFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(backups) == 0 {
+			fmt.Println("No backups")
+			return
+		}
+
+		mainPassword := readPassword("Enter main password")
+		opts, err := setupOptions()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("List of backups: \n")
+		for _, backup := range backups {
+			fmt.Printf("-------\n")
+			fmt.Printf("Number: %d\n", backup.Number)
+			fmt.Printf("Modified: %s\n", backup.ModTime.Local().Format(time.RFC822))
+
+			info, err := setup.InspectBackup(storageFileFlag, backup.Number, mainPassword, opts...)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+
+			fmt.Printf("Entries: %d\n", info.Entries)
+			if !info.LastTx.IsZero() {
+				fmt.Printf("Last change: %s\n", info.LastTx.Local().Format(time.RFC822))
+			}
+		}
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <number>",
+	Short: "Restore the vault from a backup",
+	Long:  "Restore the vault from a backup, the current vault is kept as the most recent backup",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("Backup number %q is not valid\n", args[0])
+			os.Exit(1)
+		}
+
+		mainPassword := readPassword("Enter main password")
+		opts, err := setupOptions()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		info, err := setup.InspectBackup(storageFileFlag, n, mainPassword, opts...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if !confirm(fmt.Sprintf("Restore backup %d with %d entries (Y/n)?: ", info.Number, info.Entries)) {
+			return
+		}
+
+		if err = setup.RestoreBackup(storageFileFlag, n, mainPassword, opts...); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Backup %d was restored\n", n)
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupListCmd, backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+var errNotTerminal = errors.New("stdout is not a terminal")
+
+// copyToClipboard copies text to the clipboard of the terminal with the OSC 52 escape sequence,
+// it works over ssh as long as the terminal supports OSC 52.
+func copyToClipboard(text string) error {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("copy to clipboard: %w", errNotTerminal)
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text))); err != nil {
+		return fmt.Errorf("copy to clipboard: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	compactKeepFlag    int
+	compactKeepAgeFlag string
+)
+
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Compact the tx log",
+	Long: "Replace the tx log with a snapshot of the entries, the last txs are kept with their history. " +
+		"History and trash of the replaced txs are lost, attachment contents only they referenced are deleted",
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, keepAge := viper.GetInt("compact.keep.count"), viper.GetString("compact.keep.age")
+		if cmd.Flags().Changed("keep") {
+			keep = compactKeepFlag
+		}
+
+		if cmd.Flags().Changed("keep-age") {
+			keepAge = compactKeepAgeFlag
+		}
+
+		opts, err := compactOptions(keep, keepAge)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		removed, err := store.Compact(opts...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Tx log was shortened by %d txs\n", removed)
+		pruneAttachments(store)
+	},
+}
+
+func init() {
+	compactCmd.Flags().IntVar(&compactKeepFlag, "keep", 0, "number of last txs kept, 0 keeps none (default from settings)")
+	compactCmd.Flags().StringVar(&compactKeepAgeFlag, "keep-age", "", "txs newer than the age are kept, e.g. 720h, empty keeps none (default from settings)")
+	rootCmd.AddCommand(compactCmd)
+}
@@ -1 +1,79 @@
 package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	deleteIDsFlag     []string
+	deleteNumbersFlag []int
+	deleteTitlesFlag  []string
+	deleteForceFlag   bool
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [entry...]",
+	Short: "Delete entries",
+	Long: "Delete entries selected by reference, id, number or title, several entries are deleted at once. " +
+		"An entry is referenced by its id, unique id prefix or path",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && len(deleteIDsFlag) == 0 && len(deleteNumbersFlag) == 0 && len(deleteTitlesFlag) == 0 {
+			fmt.Println("Set entries to delete with a reference, --id, --number or --title")
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		entries, err := selectEntries(store, args, deleteIDsFlag, deleteNumbersFlag, deleteTitlesFlag)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		if !deleteForceFlag {
+			buf := strings.Builder{}
+			fmt.Printf("Entries to delete: \n")
+			for _, entry := range entries {
+				fmt.Fprintf(&buf, "-------\n")
+				fmt.Fprintf(&buf, "ID: %s\n", entry.ID)
+				fmt.Fprintf(&buf, "Title: %s\n", entry.Title)
+				fmt.Fprintf(&buf, "Created: %s\n", entry.CreatedAt.Local().Format(time.RFC822))
+				fmt.Fprintf(&buf, "Updated: %s\n", entry.UpdatedAt.Local().Format(time.RFC822))
+			}
+			fmt.Print(buf.String())
+
+			if !confirm(fmt.Sprintf("Delete %d entries (Y/n)?: ", len(entries))) {
+				return
+			}
+		}
+
+		ids := make([]string, len(entries))
+		for idx, entry := range entries {
+			ids[idx] = entry.ID
+		}
+
+		if err = store.DeleteByIDs(ids...); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%d entries were deleted\n", len(entries))
+	},
+}
+
+func init() {
+	deleteCmd.Flags().StringSliceVarP(&deleteIDsFlag, "id", "i", nil, "entry id or its unique prefix, repeat to delete several entries")
+	deleteCmd.Flags().IntSliceVarP(&deleteNumbersFlag, "number", "n", nil, "entry number as shown by list")
+	deleteCmd.Flags().StringSliceVarP(&deleteTitlesFlag, "title", "t", nil, "exact entry title, all entries with the title are deleted")
+	deleteCmd.Flags().BoolVar(&deleteForceFlag, "force", false, "delete without confirmation")
+	rootCmd.AddCommand(deleteCmd)
+}
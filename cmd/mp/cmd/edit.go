@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editIDFlag           string
+	editNumberFlag       int
+	editTitleFlag        string
+	editNewTitleFlag     string
+	editNewPasswordFlag  string
+	editNewUsernameFlag  string
+	editNewURLsFlag      []string
+	editNewNotesFlag     string
+	editNewFolderFlag    string
+	editNewOTPFlag       string
+	editGenerateFlag     bool
+	editPolicyFlag       string
+	editSetFieldsFlag    []string
+	editRemoveFieldsFlag []string
+	editShowSecretsFlag  bool
+)
+
+// editFlags are the flags that set the changes instead of asking them interactively.
+var editFlags = []string{"new-title", "new-password", "new-username", "new-url", "new-notes", "new-folder", "new-otp", "generate", "set-field", "remove-field"}
+
+var editCmd = &cobra.Command{
+	Use:   "edit [entry]",
+	Short: "Edit an entry",
+	Long: "Edit an entry selected by reference, id, number or title, the reference is the id, a unique id prefix or the path. " +
+		"Without --new-* flags, --set-field and --remove-field the changes are asked interactively, empty input keeps the current value",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			ids     []string
+			numbers []int
+			titles  []string
+		)
+
+		switch {
+		case len(args) > 0:
+		case editIDFlag != "":
+			ids = append(ids, editIDFlag)
+		case editNumberFlag != 0:
+			numbers = append(numbers, editNumberFlag)
+		case editTitleFlag != "":
+			titles = append(titles, editTitleFlag)
+		default:
+			fmt.Println("Set the entry to edit with a reference, --id, --number or --title")
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		entry, err := selectEntry(store, args, ids, numbers, titles)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		var changed manager.ChangeEntry
+		if flagsChanged(cmd, editFlags...) {
+			changed, err = flagChangeEntry(cmd, entry)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else {
+			changed = readChangeEntry(entry)
+		}
+
+		changed = dropUnchanged(entry, changed)
+		if changed == (manager.ChangeEntry{}) {
+			fmt.Println("Nothing changed")
+			return
+		}
+
+		if err = store.ChangeByID(entry.ID, changed); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Entry with id %s was changed\n", entry.ID)
+		fmt.Print(changeDiff(entry, changed, editShowSecretsFlag))
+	},
+}
+
+// readChangeEntry asks new values for the entry, empty input keeps the current value.
+func readChangeEntry(entry manager.Entry) manager.ChangeEntry {
+	var changed manager.ChangeEntry
+
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Printf("Set a title (%s): ", entry.Title)
+	if scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if title := scanner.Text(); title != "" {
+			changed.Title = &title
+		}
+	}
+
+	if password := readPassword("Set password (empty keeps the current one):"); password != "" {
+		changed.Password = &password
+	}
+
+	if username := readLine(scanner, fmt.Sprintf("Set a username (%s): ", entry.Username)); username != "" {
+		changed.Username = &username
+	}
+
+	if urls := splitList(readLine(scanner, fmt.Sprintf("Set URLs, comma separated (%s): ", strings.Join(entry.URLs, ", ")))); len(urls) > 0 {
+		changed.URLs = &urls
+	}
+
+	if notes := readLine(scanner, "Set notes (empty keeps the current ones): "); notes != "" {
+		changed.Notes = &notes
+	}
+
+	if folder := manager.CleanFolder(readLine(scanner, fmt.Sprintf("Set a folder (%s): ", entry.Folder))); folder != "" {
+		changed.Folder = &folder
+	}
+
+	return changed
+}
+
+// flagChangeEntry returns the changes set by the edit flags.
+func flagChangeEntry(cmd *cobra.Command, entry manager.Entry) (manager.ChangeEntry, error) {
+	var changed manager.ChangeEntry
+	if cmd.Flags().Changed("new-title") {
+		changed.Title = &editNewTitleFlag
+	}
+
+	if cmd.Flags().Changed("new-password") {
+		changed.Password = &editNewPasswordFlag
+	}
+
+	if editGenerateFlag {
+		password, err := generatePassword(editPolicyFlag)
+		if err != nil {
+			return manager.ChangeEntry{}, err
+		}
+
+		changed.Password = &password
+	}
+
+	if cmd.Flags().Changed("new-username") {
+		changed.Username = &editNewUsernameFlag
+	}
+
+	if cmd.Flags().Changed("new-url") {
+		changed.URLs = &editNewURLsFlag
+	}
+
+	if cmd.Flags().Changed("new-notes") {
+		changed.Notes = &editNewNotesFlag
+	}
+
+	if cmd.Flags().Changed("new-folder") {
+		folder := manager.CleanFolder(editNewFolderFlag)
+		changed.Folder = &folder
+	}
+
+	if cmd.Flags().Changed("new-otp") {
+		otpSecret, err := parseOTP(editNewOTPFlag)
+		if err != nil {
+			return manager.ChangeEntry{}, err
+		}
+
+		changed.OTP = &otpSecret
+	}
+
+	if cmd.Flags().Changed("set-field") || cmd.Flags().Changed("remove-field") {
+		fields, err := editFields(entry.Fields, editSetFieldsFlag, editRemoveFieldsFlag)
+		if err != nil {
+			return manager.ChangeEntry{}, err
+		}
+
+		changed.Fields = &fields
+	}
+
+	return changed, nil
+}
+
+// editFields sets the fields replacing the ones with the same name in place and removes the named ones.
+func editFields(fields []manager.CustomField, set, remove []string) ([]manager.CustomField, error) {
+	edited := make([]manager.CustomField, len(fields))
+	copy(edited, fields)
+
+	for _, value := range set {
+		field, err := parseField(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx := fieldIndex(edited, field.Name); idx >= 0 {
+			// a field set without a type keeps its current type
+			if name, _, _ := cut(value, "="); !strings.Contains(name, ":") {
+				field.Type = edited[idx].Type
+			}
+
+			edited[idx] = field
+		} else {
+			edited = append(edited, field)
+		}
+	}
+
+	for _, name := range remove {
+		idx := fieldIndex(edited, name)
+		if idx < 0 {
+			return nil, fmt.Errorf("field %q not found", name)
+		}
+
+		edited = append(edited[:idx], edited[idx+1:]...)
+	}
+
+	return edited, nil
+}
+
+func fieldIndex(fields []manager.CustomField, name string) int {
+	for idx, field := range fields {
+		if field.Name == name {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// dropUnchanged unsets the changes equal to the current values of the entry.
+func dropUnchanged(entry manager.Entry, changed manager.ChangeEntry) manager.ChangeEntry {
+	if changed.Title != nil && *changed.Title == entry.Title {
+		changed.Title = nil
+	}
+
+	if changed.Password != nil && *changed.Password == entry.Password {
+		changed.Password = nil
+	}
+
+	if changed.Username != nil && *changed.Username == entry.Username {
+		changed.Username = nil
+	}
+
+	if changed.URLs != nil && equalStrings(*changed.URLs, entry.URLs) {
+		changed.URLs = nil
+	}
+
+	if changed.Notes != nil && *changed.Notes == entry.Notes {
+		changed.Notes = nil
+	}
+
+	if changed.Folder != nil && *changed.Folder == entry.Folder {
+		changed.Folder = nil
+	}
+
+	if changed.OTP != nil && *changed.OTP == entry.OTP {
+		changed.OTP = nil
+	}
+
+	if changed.Fields != nil && equalFields(*changed.Fields, entry.Fields) {
+		changed.Fields = nil
+	}
+
+	return changed
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalFields(a, b []manager.CustomField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func flagsChanged(cmd *cobra.Command, names ...string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func changeDiff(entry manager.Entry, changed manager.ChangeEntry, showSecrets bool) string {
+	buf := strings.Builder{}
+	if changed.Title != nil {
+		fmt.Fprintf(&buf, "Title: %s -> %s\n", entry.Title, *changed.Title)
+	}
+
+	if changed.Password != nil {
+		if showSecrets {
+			fmt.Fprintf(&buf, "Secret: %s -> %s\n", entry.Password, *changed.Password)
+		} else {
+			fmt.Fprintf(&buf, "Secret: changed\n")
+		}
+	}
+
+	if changed.Username != nil {
+		fmt.Fprintf(&buf, "Username: %s -> %s\n", entry.Username, *changed.Username)
+	}
+
+	if changed.URLs != nil {
+		fmt.Fprintf(&buf, "URLs: %s -> %s\n", strings.Join(entry.URLs, ", "), strings.Join(*changed.URLs, ", "))
+	}
+
+	if changed.Notes != nil {
+		fmt.Fprintf(&buf, "Notes: changed\n")
+	}
+
+	if changed.Folder != nil {
+		fmt.Fprintf(&buf, "Folder: %s -> %s\n", entry.Folder, *changed.Folder)
+	}
+
+	if changed.OTP != nil {
+		fmt.Fprintf(&buf, "OTP: changed\n")
+	}
+
+	if changed.Fields != nil {
+		fmt.Fprintf(&buf, "Fields: %s -> %s\n", fieldNames(entry.Fields), fieldNames(*changed.Fields))
+	}
+
+	return buf.String()
+}
+
+func fieldNames(fields []manager.CustomField) string {
+	names := make([]string, len(fields))
+	for idx, field := range fields {
+		names[idx] = field.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editIDFlag, "id", "i", "", "entry id or its unique prefix")
+	editCmd.Flags().IntVarP(&editNumberFlag, "number", "n", 0, "entry number as shown by list")
+	editCmd.Flags().StringVarP(&editTitleFlag, "title", "t", "", "exact entry title")
+	editCmd.Flags().StringVar(&editNewTitleFlag, "new-title", "", "new title")
+	editCmd.Flags().StringVar(&editNewPasswordFlag, "new-password", "", "new password, it is kept in the shell history")
+	editCmd.Flags().BoolVarP(&editGenerateFlag, "generate", "g", false, "generate a new password")
+	editCmd.Flags().StringVar(&editPolicyFlag, "policy", "", "policy of the generated password, generate.policy by default")
+	editCmd.Flags().StringVar(&editNewUsernameFlag, "new-username", "", "new username")
+	editCmd.Flags().StringSliceVar(&editNewURLsFlag, "new-url", nil, "new urls replacing the current ones, can be repeated or comma separated")
+	editCmd.Flags().StringVar(&editNewNotesFlag, "new-notes", "", "new notes")
+	editCmd.Flags().StringVar(&editNewFolderFlag, "new-folder", "", "new folder path, empty moves the entry out of folders")
+	editCmd.Flags().StringVar(&editNewOTPFlag, "new-otp", "", "new TOTP otpauth:// URI or base32 seed, empty removes it")
+	editCmd.Flags().StringArrayVar(&editSetFieldsFlag, "set-field", nil,
+		"set a custom field as [type:]name=value replacing the one with the same name, can be repeated")
+	editCmd.Flags().StringArrayVar(&editRemoveFieldsFlag, "remove-field", nil, "remove the custom field by name, can be repeated")
+	editCmd.Flags().BoolVar(&editShowSecretsFlag, "show-secrets", false, "print old and new secrets")
+	rootCmd.AddCommand(editCmd)
+}
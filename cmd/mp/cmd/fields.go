@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+)
+
+const maskedSecret = "********"
+
+var fieldTypes = map[string]byte{
+	"text":   manager.CustomFieldText,
+	"hidden": manager.CustomFieldHidden,
+	"url":    manager.CustomFieldURL,
+	"email":  manager.CustomFieldEmail,
+}
+
+// parseField parses a custom field set as [type:]name=value, the type is text by default.
+func parseField(s string) (manager.CustomField, error) {
+	name, value, ok := cut(s, "=")
+	if !ok || name == "" {
+		return manager.CustomField{}, fmt.Errorf("field %q is not [type:]name=value", s)
+	}
+
+	field := manager.CustomField{Name: name, Value: value, Type: manager.CustomFieldText}
+	if typ, rest, ok := cut(name, ":"); ok {
+		t, known := fieldTypes[typ]
+		if !known {
+			return manager.CustomField{}, fmt.Errorf("field %q has unknown type %q, use text, hidden, url or email", s, typ)
+		}
+
+		if rest == "" {
+			return manager.CustomField{}, fmt.Errorf("field %q has no name", s)
+		}
+
+		field.Name, field.Type = rest, t
+	}
+
+	return field, nil
+}
+
+func parseFields(values []string) ([]manager.CustomField, error) {
+	fields := make([]manager.CustomField, 0, len(values))
+	for _, value := range values {
+		field, err := parseField(value)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func fieldTypeName(t byte) string {
+	for name, typ := range fieldTypes {
+		if typ == t {
+			return name
+		}
+	}
+
+	return "text"
+}
+
+// fieldValue returns the value of the field, hidden fields are masked unless showSecrets.
+func fieldValue(field manager.CustomField, showSecrets bool) string {
+	if field.Type == manager.CustomFieldHidden && !showSecrets {
+		return maskedSecret
+	}
+
+	return field.Value
+}
+
+// splitList splits a comma separated list dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// cut is strings.Cut which is not available in go 1.17.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/polylab/mypass-cli/internal/generate"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	generatePolicyFlag string
+	generateLengthFlag int
+	generateWordsFlag  int
+	generateCountFlag  int
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate passwords",
+	Long: "Generate passwords or diceware passphrases by a policy of generate.policies in settings.yaml, " +
+		"generate.policy is used without --policy",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := policyFor(generatePolicyFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if cmd.Flags().Changed("length") {
+			policy = policy.WithLength(generateLengthFlag)
+		}
+
+		if cmd.Flags().Changed("words") {
+			policy.Words = generateWordsFlag
+		}
+
+		for i := 0; i < generateCountFlag; i++ {
+			password, err := generate.Generate(policy)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println(password)
+		}
+
+		fmt.Fprintf(os.Stderr, "Entropy: %.0f bits\n", generate.Entropy(policy))
+	},
+}
+
+// policyFor returns the policy of generate.policies with the name, generate.policy when the name is empty.
+func policyFor(name string) (generate.Policy, error) {
+	if name == "" {
+		name = viper.GetString("generate.policy")
+	}
+
+	key := "generate.policies." + name
+	if !viper.IsSet(key) {
+		return generate.Policy{}, fmt.Errorf("policy %q: %w", name, generate.ErrPolicyNotValid)
+	}
+
+	return generate.Policy{
+		Length:           viper.GetInt(key + ".length"),
+		Lower:            viper.GetBool(key + ".lower"),
+		Upper:            viper.GetBool(key + ".upper"),
+		Digits:           viper.GetBool(key + ".digits"),
+		Symbols:          viper.GetBool(key + ".symbols"),
+		ExcludeAmbiguous: viper.GetBool(key + ".exclude_ambiguous"),
+		Require:          viper.GetBool(key + ".require"),
+		Words:            viper.GetInt(key + ".words"),
+		Separator:        viper.GetString(key + ".separator"),
+	}, nil
+}
+
+// generatePassword generates a password by the named policy.
+func generatePassword(name string) (string, error) {
+	policy, err := policyFor(name)
+	if err != nil {
+		return "", err
+	}
+
+	return generate.Generate(policy)
+}
+
+func init() {
+	generateCmd.Flags().StringVar(&generatePolicyFlag, "policy", "", "policy name of generate.policies")
+	generateCmd.Flags().IntVarP(&generateLengthFlag, "length", "l", 0, "password length, overrides the policy, passphrase policies use the default character classes")
+	generateCmd.Flags().IntVarP(&generateWordsFlag, "words", "w", 0, "number of passphrase words, overrides the policy")
+	generateCmd.Flags().IntVarP(&generateCountFlag, "count", "n", 1, "number of passwords")
+	rootCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyIDFlag          string
+	historyShowSecretsFlag bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [entry]",
+	Short: "Show the change history of an entry",
+	Long: "Show every add, update and delete of an entry with the tx hash, past secrets are printed with --show-secrets only. " +
+		"The entry is selected by reference or id, a deleted entry by its full id",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := historyIDFlag
+		if len(args) > 0 {
+			ref = args[0]
+		}
+
+		if ref == "" {
+			fmt.Println("Set the entry with a reference or --id")
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// a deleted entry is not resolved, its history is looked up by the full id
+		id := ref
+		var entry manager.Entry
+		if len(args) > 0 {
+			entry, err = store.Resolve(ref)
+		} else {
+			entry, err = store.ResolveID(ref)
+		}
+
+		switch {
+		case err == nil:
+			id = entry.ID
+		case !errors.Is(err, manager.ErrNotFound):
+			printError(err)
+			os.Exit(1)
+		}
+
+		revisions, err := store.History(id)
+		if err != nil {
+			fmt.Println(fmt.Errorf("entry %s: %w", ref, err))
+			os.Exit(1)
+		}
+
+		fmt.Printf("History of the entry with id %s: \n", id)
+		fmt.Print(formatHistory(revisions, historyShowSecretsFlag))
+	},
+}
+
+func formatHistory(revisions []manager.Revision, showSecrets bool) string {
+	buf := strings.Builder{}
+	for _, revision := range revisions {
+		fmt.Fprintf(&buf, "-------\n")
+		fmt.Fprintf(&buf, "Tx: %s\n", revision.Hex())
+		fmt.Fprintf(&buf, "Date: %s\n", revision.Ts.Local().Format(time.RFC822))
+
+		switch revision.Kind {
+		case manager.TxKindAdd:
+			fmt.Fprintf(&buf, "Action: added\n")
+		case manager.TxKindUpdate:
+			fmt.Fprintf(&buf, "Action: updated\n")
+		case manager.TxKindDel:
+			fmt.Fprintf(&buf, "Action: deleted\n")
+		case manager.TxKindSnapshot:
+			fmt.Fprintf(&buf, "Action: compacted\n")
+		}
+
+		fmt.Fprintf(&buf, "Title: %s\n", revision.Entry.Title)
+		if showSecrets {
+			fmt.Fprintf(&buf, "Secret: %s\n", revision.Entry.Password)
+		} else if revision.Kind == manager.TxKindUpdate && revision.Fields&manager.FieldPassword != 0 {
+			fmt.Fprintf(&buf, "Secret: changed\n")
+		}
+	}
+
+	return buf.String()
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&historyIDFlag, "id", "i", "", "entry id or its unique prefix")
+	historyCmd.Flags().BoolVar(&historyShowSecretsFlag, "show-secrets", false, "print past secrets")
+	rootCmd.AddCommand(historyCmd)
+}
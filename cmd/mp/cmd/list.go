@@ -1,42 +1,176 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
-	"github.com/spf13/cobra"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+var (
+	listColumnsFlag     []string
+	listShowSecretsFlag bool
+	listTagsFlag        []string
+	listFolderFlag      string
+)
+
+type column struct {
+	header string
+	value  func(number int, entry manager.Entry, showSecrets bool) string
+}
+
+// columns are the columns list can print by name.
+var columns = map[string]column{
+	"number": {header: "NUMBER", value: func(number int, _ manager.Entry, _ bool) string { return strconv.Itoa(number) }},
+	"id":     {header: "ID", value: func(_ int, entry manager.Entry, _ bool) string { return entry.ID }},
+	"title":  {header: "TITLE", value: func(_ int, entry manager.Entry, _ bool) string { return entry.Title }},
+	"path":   {header: "PATH", value: func(_ int, entry manager.Entry, _ bool) string { return entry.Path() }},
+	"folder": {header: "FOLDER", value: func(_ int, entry manager.Entry, _ bool) string { return entry.Folder }},
+	"tags": {header: "TAGS", value: func(_ int, entry manager.Entry, _ bool) string {
+		return strings.Join(entry.Tags, ", ")
+	}},
+	"username": {header: "USERNAME", value: func(_ int, entry manager.Entry, _ bool) string {
+		return entry.Username
+	}},
+	"url": {header: "URL", value: func(_ int, entry manager.Entry, _ bool) string {
+		return strings.Join(entry.URLs, ", ")
+	}},
+	"secret": {header: "SECRET", value: func(_ int, entry manager.Entry, showSecrets bool) string {
+		if !showSecrets {
+			return maskedSecret
+		}
+
+		return entry.Password
+	}},
+	"created": {header: "CREATED", value: func(_ int, entry manager.Entry, _ bool) string {
+		return entry.CreatedAt.Local().Format(time.RFC822)
+	}},
+	"updated": {header: "UPDATED", value: func(_ int, entry manager.Entry, _ bool) string {
+		return entry.UpdatedAt.Local().Format(time.RFC822)
+	}},
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "",
-	Long:  "",
+	Short: "List entries",
+	Long: "List entries as a table, secrets are masked unless --show-secrets is confirmed. " +
+		"--folder lists the entries in the folder and its subfolders, --tag the entries with all the tags. " +
+		"The columns are number, id, title, path, folder, tags, username, url, secret, created and updated",
 	Run: func(cmd *cobra.Command, args []string) {
-		store, err := provide()
+		names := viper.GetStringSlice("list.columns")
+		if cmd.Flags().Changed("columns") {
+			names = listColumnsFlag
+		}
+
+		selected, err := selectColumns(names)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 
-		entries := store.List()
-		buf := strings.Builder{}
-		defer buf.Reset()
-		fmt.Printf("List of your secrets: \n")
-		for idx, entry := range entries {
-			fmt.Fprintf(&buf, "-------\n")
-			fmt.Fprintf(&buf, "Number: %d\n", idx+1)
-			fmt.Fprintf(&buf, "ID: %s\n", entry.ID)
-			fmt.Fprintf(&buf, "Title: %s\n", entry.Title)
-			fmt.Fprintf(&buf, "Secret: %s\n", entry.Password)
-			fmt.Fprintf(&buf, "Created: %s\n", entry.CreatedAt.Local().Format(time.RFC822))
-			fmt.Fprintf(&buf, "Updated: %s\n", entry.UpdatedAt.Local().Format(time.RFC822))
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
 		}
 
-		fmt.Print(buf.String())
+		showSecrets := listShowSecretsFlag && confirm("Show secrets in clear text (Y/n)?: ")
+		printTable(os.Stdout, filterEntries(store, listFolderFlag, listTagsFlag), selected, showSecrets)
 	},
 }
 
+func selectColumns(names []string) ([]column, error) {
+	selected := make([]column, 0, len(names))
+	for _, name := range names {
+		c, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q, use number, id, title, path, folder, tags, username, url, secret, created or updated", name)
+		}
+
+		selected = append(selected, c)
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no columns to list")
+	}
+
+	return selected, nil
+}
+
+// numbered is an entry with its number as shown by list.
+type numbered struct {
+	manager.Entry
+	number int
+}
+
+// filterEntries returns the entries in the folder with all the tags numbered as in List.
+func filterEntries(store *manager.Store, folder string, tags []string) []numbered {
+	selected := make(map[string]bool)
+	for _, entry := range store.ListByFolder(folder) {
+		selected[entry.ID] = true
+	}
+
+	for _, tag := range manager.CleanTags(tags) {
+		tagged := make(map[string]bool)
+		for _, entry := range store.ListByTag(tag) {
+			tagged[entry.ID] = selected[entry.ID]
+		}
+
+		selected = tagged
+	}
+
+	entries := make([]numbered, 0, len(selected))
+	for idx, entry := range store.List() {
+		if selected[entry.ID] {
+			entries = append(entries, numbered{Entry: entry, number: idx + 1})
+		}
+	}
+
+	return entries
+}
+
+func printTable(w io.Writer, entries []numbered, selected []column, showSecrets bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	cells := make([]string, len(selected))
+	for idx, c := range selected {
+		cells[idx] = c.header
+	}
+
+	fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	for _, entry := range entries {
+		for idx, c := range selected {
+			cells[idx] = c.value(entry.number, entry.Entry, showSecrets)
+		}
+
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	tw.Flush()
+}
+
+// confirm asks the question and reports whether it was answered with Y.
+func confirm(question string) bool {
+	fmt.Print(question)
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return false
+	}
+
+	return scanner.Text() == "Y"
+}
+
 func init() {
+	listCmd.Flags().StringSliceVar(&listColumnsFlag, "columns", nil, "columns to print, comma separated, overrides list.columns")
+	listCmd.Flags().StringSliceVarP(&listTagsFlag, "tag", "t", nil, "list entries with the tag, can be repeated or comma separated")
+	listCmd.Flags().StringVar(&listFolderFlag, "folder", "", "list entries in the folder and its subfolders")
+	listCmd.Flags().BoolVar(&listShowSecretsFlag, "show-secrets", false, "print secrets in clear text after a confirmation")
 	rootCmd.AddCommand(listCmd)
 }
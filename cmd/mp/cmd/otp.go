@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/otp"
+	"github.com/spf13/cobra"
+)
+
+var otpCmd = &cobra.Command{
+	Use:   "otp <entry>",
+	Short: "Print the current TOTP code of an entry",
+	Long:  "Print the current TOTP code of an entry selected by reference with the seconds it stays valid",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		entry, err := selectEntry(store, args, nil, nil, nil)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		if entry.OTP == "" {
+			fmt.Printf("Entry %s has no OTP, set it with mp edit --new-otp\n", entry.Path())
+			os.Exit(1)
+		}
+
+		key, err := otp.Parse(entry.OTP)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		code, err := key.Code(now)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Code: %s\n", code)
+		fmt.Printf("Valid for: %ds\n", int(key.Remaining(now)/time.Second))
+	},
+}
+
+// parseOTP validates the otpauth URI or the base32 seed, empty s is no OTP.
+func parseOTP(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	if _, err := otp.Parse(s); err != nil {
+		return "", fmt.Errorf("otp: %w", err)
+	}
+
+	return s, nil
+}
+
+// describeOTP describes the OTP of the entry without its secret.
+func describeOTP(entry manager.Entry) string {
+	key, err := otp.Parse(entry.OTP)
+	if err != nil {
+		return err.Error()
+	}
+
+	params := fmt.Sprintf("%s, %d digits, %s", otp.AlgorithmName(key.Algorithm), key.Digits, key.Period)
+	if key.Issuer == "" {
+		return params
+	}
+
+	return fmt.Sprintf("%s (%s)", key.Issuer, params)
+}
+
+func init() {
+	rootCmd.AddCommand(otpCmd)
+}
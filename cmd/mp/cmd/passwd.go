@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/polylab/mypass-cli/internal/setup"
+	"github.com/spf13/cobra"
+)
+
+var passwdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Change the main password",
+	Long:  "Change the main password and re-encrypt the vault and its backups, --aes/--xchacha and the kdf settings apply to the new key",
+	Run: func(cmd *cobra.Command, args []string) {
+		oldPassword := readPassword("Enter main password")
+		newPassword := readPassword("Enter new main password")
+		repeated := readPassword("Repeat new main password")
+
+		if newPassword == "" {
+			fmt.Println("New password is empty")
+			os.Exit(1)
+		}
+
+		if newPassword != repeated {
+			fmt.Println("Passwords do not match")
+			os.Exit(1)
+		}
+
+		opts, err := setupOptions()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		skipped, err := setup.ChangePassword(storageFileFlag, oldPassword, newPassword, opts...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Print("Main password was changed\n")
+		for _, backup := range skipped {
+			fmt.Printf("Backup %d is not encrypted with the old main password and was kept as it is\n", backup.Number)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(passwdCmd)
+}
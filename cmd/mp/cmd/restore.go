@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var restoreTxFlag string
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore an entry from the tx log",
+	Long: "Restore the entry to its state after the tx, the hash is shown by history and trash. " +
+		"A deleted entry is added again with its original id",
+	Run: func(cmd *cobra.Command, args []string) {
+		if restoreTxFlag == "" {
+			fmt.Println("Set the tx to restore with --tx")
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		entry, err := store.RestoreFromTx(restoreTxFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Entry with id %s was restored\n", entry.ID)
+		fmt.Printf("Title: %s\n", entry.Title)
+	},
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&restoreTxFlag, "tx", "", "tx hash or its unique prefix of at least 4 characters")
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -8,6 +8,10 @@ import (
 	"path/filepath"
 
 	"github.com/mitchellh/go-homedir"
+	"github.com/polylab/mypass-cli/internal/attach"
+	"github.com/polylab/mypass-cli/internal/audit"
+	"github.com/polylab/mypass-cli/internal/crypt"
+	"github.com/polylab/mypass-cli/internal/generate"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -21,6 +25,7 @@ var (
 	debugFlag       bool
 	aes             bool
 	des             bool
+	xchacha         bool
 )
 
 var rootCmd = &cobra.Command{
@@ -46,8 +51,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFileFlag, "config", "c", "", "config file (default is $HOME/.config/mp/settings.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&storageFileFlag, "file", "f", "", "storage file (default is $HOME/.mp/db.bin)")
 	rootCmd.PersistentFlags().BoolVarP(&debugFlag, "debug", "d", false, "debug")
-	rootCmd.PersistentFlags().BoolVar(&aes, "aes", false, "aes")
+	rootCmd.PersistentFlags().BoolVar(&aes, "aes", false, "aes-256-gcm")
 	rootCmd.PersistentFlags().BoolVar(&des, "des", false, "des")
+	rootCmd.PersistentFlags().BoolVar(&xchacha, "xchacha", false, "xchacha20-poly1305")
+	_ = rootCmd.PersistentFlags().MarkDeprecated("des", "des vaults are read-only and upgraded on the next write")
 	initConfig()
 }
 
@@ -111,6 +118,36 @@ func initConfig() {
 		viper.SetConfigName("settings")
 	}
 
+	argon2id, scrypt := crypt.Argon2idParams(), crypt.ScryptParams()
+	viper.SetDefault("kdf.algorithm", "argon2id")
+	viper.SetDefault("kdf.argon2id.time", argon2id.Time)
+	viper.SetDefault("kdf.argon2id.memory", argon2id.Memory)
+	viper.SetDefault("kdf.argon2id.threads", argon2id.Threads)
+	viper.SetDefault("kdf.scrypt.n", scrypt.Time)
+	viper.SetDefault("kdf.scrypt.r", scrypt.Memory)
+	viper.SetDefault("kdf.scrypt.p", scrypt.Threads)
+	viper.SetDefault("backup.count", 5)
+	viper.SetDefault("tx.hash", "sha256")
+	viper.SetDefault("compact.threshold", 1000)
+	viper.SetDefault("compact.keep.count", 100)
+	viper.SetDefault("compact.keep.age", "720h")
+	viper.SetDefault("attach.max_size", attach.DefaultMaxSize)
+	policy := generate.DefaultPolicy()
+	viper.SetDefault("generate.policy", "default")
+	viper.SetDefault("generate.policies.default.length", policy.Length)
+	viper.SetDefault("generate.policies.default.lower", policy.Lower)
+	viper.SetDefault("generate.policies.default.upper", policy.Upper)
+	viper.SetDefault("generate.policies.default.digits", policy.Digits)
+	viper.SetDefault("generate.policies.default.symbols", policy.Symbols)
+	viper.SetDefault("generate.policies.default.require", policy.Require)
+	viper.SetDefault("generate.policies.pin.length", 6)
+	viper.SetDefault("generate.policies.pin.digits", true)
+	viper.SetDefault("generate.policies.passphrase.words", 6)
+	viper.SetDefault("generate.policies.passphrase.separator", " ")
+	viper.SetDefault("audit.min_score", audit.DefaultMinScore)
+	viper.SetDefault("audit.max_age", audit.DefaultMaxAge.String())
+	viper.SetDefault("list.columns", []string{"number", "id", "title", "username", "secret", "updated"})
+
 	viper.AutomaticEnv()
 
 	if err = viper.ReadInConfig(); err == nil {
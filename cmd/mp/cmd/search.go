@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchPickFlag  bool
+	searchCopyFlag  bool
+	searchLimitFlag int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search entries",
+	Long: "Fuzzy search entries by title, username, URL, folder and tags, the best matches are listed first. " +
+		"With --pick the matches are numbered and the picked entry is shown, " +
+		"with --copy the secret of the picked entry is copied to the clipboard of the terminal instead",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var query string
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		if searchLimitFlag < 1 {
+			fmt.Println("Limit must be at least 1")
+			os.Exit(1)
+		}
+
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		entries := store.List()
+		if !searchPickFlag && !searchCopyFlag {
+			results := search.Search(entries, query)
+			fmt.Print(formatResults(results, searchLimitFlag))
+
+			if len(results) == 0 {
+				os.Exit(1)
+			}
+
+			return
+		}
+
+		entry, ok := pickEntry(entries, query, searchLimitFlag)
+		if !ok {
+			return
+		}
+
+		if !searchCopyFlag {
+			printEntry(entry, false)
+			return
+		}
+
+		if err = copyToClipboard(entry.Password); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Secret of %s was copied to the clipboard\n", entry.Path())
+	},
+}
+
+// pickEntry lists matches of query and reads the number of the picked entry.
+// Other input replaces the query, empty input cancels.
+func pickEntry(entries []manager.Entry, query string, limit int) (manager.Entry, bool) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		results := search.Search(entries, query)
+		if len(results) > limit {
+			results = results[:limit]
+		}
+
+		fmt.Print(formatResults(results, limit))
+		fmt.Printf("Pick a number, type a new query or press enter to cancel (%s): ", query)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			return manager.Entry{}, false
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return manager.Entry{}, false
+		}
+
+		if number, err := strconv.Atoi(input); err == nil && number > 0 && number <= len(results) {
+			return results[number-1].Entry, true
+		}
+
+		query = input
+	}
+}
+
+func formatResults(results []search.Result, limit int) string {
+	if len(results) == 0 {
+		return "No entries found\n"
+	}
+
+	buf := strings.Builder{}
+	for idx, result := range results {
+		if idx == limit {
+			fmt.Fprintf(&buf, "... %d more\n", len(results)-limit)
+			break
+		}
+
+		fmt.Fprintf(&buf, "%3d  %s  %s\n", idx+1, shortID(result.Entry.ID), result.Entry.Path())
+	}
+
+	return buf.String()
+}
+
+func init() {
+	searchCmd.Flags().BoolVarP(&searchPickFlag, "pick", "p", false, "pick an entry from the matches")
+	searchCmd.Flags().BoolVar(&searchCopyFlag, "copy", false, "pick an entry and copy its secret to the clipboard with OSC 52")
+	searchCmd.Flags().IntVar(&searchLimitFlag, "limit", 20, "max number of matches listed")
+	rootCmd.AddCommand(searchCmd)
+}
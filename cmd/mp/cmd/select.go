@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+)
+
+// selectEntries returns entries matching any of refs resolved by id, unique id prefix or path,
+// ids or their unique prefixes, numbers as shown by list or exact titles.
+func selectEntries(store *manager.Store, refs, ids []string, numbers []int, titles []string) ([]manager.Entry, error) {
+	entries := make([]manager.Entry, 0)
+	seen := make(map[string]struct{})
+	add := func(entry manager.Entry) {
+		if _, ok := seen[entry.ID]; !ok {
+			seen[entry.ID] = struct{}{}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, ref := range refs {
+		entry, err := store.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		add(entry)
+	}
+
+	for _, id := range ids {
+		entry, err := store.ResolveID(id)
+		if err != nil {
+			return nil, err
+		}
+		add(entry)
+	}
+
+	for _, number := range numbers {
+		entry, ok := store.FindByNumber(number - 1)
+		if !ok {
+			return nil, fmt.Errorf("entry with number %d: %w", number, manager.ErrNotFound)
+		}
+		add(entry)
+	}
+
+	for _, title := range titles {
+		found := store.FindByTitle(title)
+		if len(found) == 0 {
+			return nil, fmt.Errorf("entry with title %q: %w", title, manager.ErrNotFound)
+		}
+
+		for _, entry := range found {
+			add(entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// selectEntry returns the single entry selected by selectEntries.
+func selectEntry(store *manager.Store, refs, ids []string, numbers []int, titles []string) (manager.Entry, error) {
+	entries, err := selectEntries(store, refs, ids, numbers, titles)
+	if err != nil {
+		return manager.Entry{}, err
+	}
+
+	if len(entries) > 1 {
+		ref := strings.Join(append(append(append([]string(nil), refs...), ids...), titles...), ", ")
+		return manager.Entry{}, &manager.AmbiguousError{Ref: ref, Candidates: entries}
+	}
+
+	return entries[0], nil
+}
+
+// printError prints err, candidates of an ambiguous reference are listed to pick one by id.
+func printError(err error) {
+	fmt.Println(err)
+
+	var ambiguous *manager.AmbiguousError
+	if errors.As(err, &ambiguous) {
+		fmt.Println("Select one of the entries by id:")
+		for _, entry := range ambiguous.Candidates {
+			fmt.Printf("%s  %s\n", entry.ID, entry.Path())
+		}
+	}
+}
+
+// shortID returns the first 8 characters of id, enough to select the entry in most vaults.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+
+	return id
+}
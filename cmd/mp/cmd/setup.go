@@ -2,33 +2,146 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/crypt"
 	"github.com/polylab/mypass-cli/internal/manager"
 	"github.com/polylab/mypass-cli/internal/setup"
+	"github.com/spf13/viper"
 	"golang.org/x/term"
-	"os"
 )
 
 func provide() (*manager.Store, error) {
-	fmt.Printf("Enter main password\n")
-	mainPasswordBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	mainPassword := readPassword("Enter main password")
+
+	opts, err := setupOptions()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return nil, err
 	}
 
+	s, err := setup.Provide(storageFileFlag, mainPassword, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("provider: %w", err)
+	}
+
+	return s, nil
+}
+
+func setupOptions() ([]setup.Option, error) {
 	var opts []setup.Option
 	switch {
 	case aes:
 		opts = append(opts, setup.WithAES())
-	case des:
-		opts = append(opts, setup.WithDES())
+	case xchacha:
+		opts = append(opts, setup.WithXChaCha20())
 	default:
 	}
 
-	s, err := setup.Provide(storageFileFlag, string(mainPasswordBytes), opts...)
+	kdf, err := kdfParams()
 	if err != nil {
-		return nil, fmt.Errorf("provider: %w", err)
+		return nil, err
 	}
+	opts = append(opts, setup.WithKDF(kdf), setup.WithBackups(viper.GetInt("backup.count")))
 
-	return s, nil
+	txHash, err := txHashAlg()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, setup.WithTxHash(txHash))
+
+	keep, err := compactOptions(viper.GetInt("compact.keep.count"), viper.GetString("compact.keep.age"))
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, setup.WithCompaction(viper.GetInt("compact.threshold"), keep...))
+
+	return opts, nil
+}
+
+// compactOptions returns options keeping count txs and txs not older than age after the snapshot.
+func compactOptions(count int, age string) ([]manager.CompactOption, error) {
+	opts := []manager.CompactOption{manager.WithKeepTxs(count)}
+	if age != "" {
+		d, err := time.ParseDuration(age)
+		if err != nil {
+			return nil, fmt.Errorf("keep age: %w", err)
+		}
+		opts = append(opts, manager.WithKeepAge(d))
+	}
+
+	return opts, nil
+}
+
+func txHashAlg() (byte, error) {
+	switch alg := viper.GetString("tx.hash"); alg {
+	case "sha256":
+		return manager.HashSHA256, nil
+	case "blake2b":
+		return manager.HashBLAKE2b, nil
+	default:
+		return 0, fmt.Errorf("tx hash %q: %w", alg, manager.ErrHashNotSupport)
+	}
+}
+
+// readPassword prints prompt to stderr and reads a password from the terminal without echo.
+func readPassword(prompt string) string {
+	fmt.Fprintln(os.Stderr, prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return string(password)
+}
+
+func kdfParams() (crypt.KDFParams, error) {
+	var (
+		params crypt.KDFParams
+		keys   [3]string
+	)
+
+	switch alg := viper.GetString("kdf.algorithm"); alg {
+	case "argon2id":
+		params.ID, keys = crypt.KDFArgon2id, [3]string{"kdf.argon2id.time", "kdf.argon2id.memory", "kdf.argon2id.threads"}
+	case "scrypt":
+		params.ID, keys = crypt.KDFScrypt, [3]string{"kdf.scrypt.n", "kdf.scrypt.r", "kdf.scrypt.p"}
+	default:
+		return params, fmt.Errorf("kdf %q: %w", alg, crypt.ErrKDFNotSupport)
+	}
+
+	timeCost, err := configUint(keys[0], math.MaxUint32)
+	if err != nil {
+		return params, err
+	}
+
+	memoryCost, err := configUint(keys[1], math.MaxUint32)
+	if err != nil {
+		return params, err
+	}
+
+	threads, err := configUint(keys[2], math.MaxUint8)
+	if err != nil {
+		return params, err
+	}
+
+	params.Time, params.Memory, params.Threads = uint32(timeCost), uint32(memoryCost), uint8(threads)
+	if err = params.Validate(); err != nil {
+		return params, fmt.Errorf("kdf: %w", err)
+	}
+
+	return params, nil
+}
+
+// configUint reads the setting key as an unsigned integer not greater than max,
+// the value is rejected instead of wrapping around when it is narrowed.
+func configUint(key string, max uint64) (uint64, error) {
+	v := viper.GetUint64(key)
+	if v > max {
+		return 0, fmt.Errorf("%s %d is greater than %d: %w", key, v, max, crypt.ErrKDFParamsNotValid)
+	}
+
+	return v, nil
 }
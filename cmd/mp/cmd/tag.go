@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage entry tags",
+	Long:  "",
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <entry> <tag>...",
+	Short: "Add tags to an entry",
+	Long:  "Add tags to an entry selected by reference, the reference is the id, a unique id prefix or the path",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTags(args[0], args[1:], (*manager.Store).Tag)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <entry> <tag>...",
+	Short: "Remove tags from an entry",
+	Long:  "Remove tags from an entry selected by reference, the reference is the id, a unique id prefix or the path",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTags(args[0], args[1:], (*manager.Store).Untag)
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags with the number of tagged entries",
+	Long:  "",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		tags := store.Tags()
+		if len(tags) == 0 {
+			fmt.Println("No tags")
+			return
+		}
+
+		for _, tag := range tags {
+			fmt.Printf("%s (%d)\n", tag, len(store.ListByTag(tag)))
+		}
+	},
+}
+
+// changeTags applies change with tags to the entry referenced by ref and prints the resulting tags.
+func changeTags(ref string, tags []string, change func(*manager.Store, string, ...string) error) {
+	store, err := provide()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	entry, err := selectEntry(store, []string{ref}, nil, nil, nil)
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+
+	if err = change(store, entry.ID, tags...); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	changed, _ := store.FindByID(entry.ID)
+	fmt.Printf("Tags of %s: %s\n", changed.Path(), strings.Join(changed.Tags, ", "))
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd, tagListCmd)
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List deleted entries",
+	Long:  "List deleted entries with the hash of the delete tx, restore them with mp restore --tx",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		buf := strings.Builder{}
+		fmt.Printf("Deleted entries: \n")
+		for _, revision := range store.Trash() {
+			fmt.Fprintf(&buf, "-------\n")
+			fmt.Fprintf(&buf, "Tx: %s\n", revision.Hex())
+			fmt.Fprintf(&buf, "ID: %s\n", revision.Entry.ID)
+			fmt.Fprintf(&buf, "Title: %s\n", revision.Entry.Title)
+			fmt.Fprintf(&buf, "Deleted: %s\n", revision.Ts.Local().Format(time.RFC822))
+		}
+
+		fmt.Print(buf.String())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the tx log",
+	Long:  "Walk the hash chain of the tx log and report the first tx that was dropped, reordered or changed",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := provide()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err = store.Verify(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Tx log is valid")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
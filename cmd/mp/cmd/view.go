@@ -2,41 +2,100 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/spf13/cobra"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/spf13/cobra"
 )
 
-var idFlag string
+var (
+	idFlag              string
+	viewShowSecretsFlag bool
+)
 
 var viewCmd = &cobra.Command{
-	Use:   "view",
-	Short: "",
-	Long:  "",
+	Use:   "view [entry]",
+	Short: "View an entry",
+	Long:  "View an entry selected by reference or id, the reference is the id, a unique id prefix or the path",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		var ids []string
+		if idFlag != "" {
+			ids = append(ids, idFlag)
+		}
+
+		if len(args) == 0 && len(ids) == 0 {
+			fmt.Println("Set the entry to view with a reference or --id")
+			os.Exit(1)
+		}
+
 		store, err := provide()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 
-		entry, ok := store.FindByID(idFlag)
-		if !ok {
-			fmt.Println("Secret not found")
+		entry, err := selectEntry(store, args, ids, nil, nil)
+		if err != nil {
+			printError(err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("Entry with id %s was found\n", idFlag)
-		fmt.Printf("-------\n")
-		fmt.Printf("ID: %s\n", entry.ID)
-		fmt.Printf("Title: %s\n", entry.Title)
-		fmt.Printf("Secret: %s\n", entry.Password)
-		fmt.Printf("Created: %s\n", entry.CreatedAt.Local().Format(time.RFC822))
-		fmt.Printf("Updated: %s\n", entry.UpdatedAt.Local().Format(time.RFC822))
+		printEntry(entry, viewShowSecretsFlag)
 	},
 }
 
+// printEntry prints the entry, hidden custom fields are masked unless showSecrets.
+func printEntry(entry manager.Entry, showSecrets bool) {
+	fmt.Printf("Entry with id %s was found\n", entry.ID)
+	fmt.Printf("-------\n")
+	fmt.Printf("ID: %s\n", entry.ID)
+	fmt.Printf("Title: %s\n", entry.Title)
+	if entry.Folder != "" {
+		fmt.Printf("Folder: %s\n", entry.Folder)
+	}
+
+	if len(entry.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(entry.Tags, ", "))
+	}
+
+	fmt.Printf("Secret: %s\n", entry.Password)
+	if entry.Username != "" {
+		fmt.Printf("Username: %s\n", entry.Username)
+	}
+
+	for _, url := range entry.URLs {
+		fmt.Printf("URL: %s\n", url)
+	}
+
+	if entry.Notes != "" {
+		fmt.Printf("Notes: %s\n", entry.Notes)
+	}
+
+	if entry.OTP != "" {
+		if showSecrets {
+			fmt.Printf("OTP: %s\n", entry.OTP)
+		} else {
+			fmt.Printf("OTP: %s\n", describeOTP(entry))
+		}
+	}
+
+	for _, attachment := range entry.Attachments {
+		fmt.Printf("Attachment: %s (%d bytes)\n", attachment.Name, attachment.Size)
+	}
+
+	for _, field := range entry.Fields {
+		fmt.Printf("%s (%s): %s\n", field.Name, fieldTypeName(field.Type), fieldValue(field, showSecrets))
+	}
+
+	fmt.Printf("Created: %s\n", entry.CreatedAt.Local().Format(time.RFC822))
+	fmt.Printf("Updated: %s\n", entry.UpdatedAt.Local().Format(time.RFC822))
+}
+
 func init() {
-	viewCmd.PersistentFlags().StringVarP(&idFlag, "id", "i", "", "entry id")
+	viewCmd.PersistentFlags().StringVarP(&idFlag, "id", "i", "", "entry id or its unique prefix")
+	viewCmd.Flags().BoolVar(&viewShowSecretsFlag, "show-secrets", false, "print hidden custom fields and the OTP secret")
 	rootCmd.AddCommand(viewCmd)
 }
@@ -0,0 +1,138 @@
+// Package attach stores files of entries as encrypted blobs in a content-addressed directory next to the vault.
+//
+// Every distinct content is sealed once with its own random key. The key, the content hash and the blob name
+// are kept in the entry inside the vault, so the blobs are as safe as the vault and changing the main password
+// does not re-encrypt them. Attaching a content that is already in the vault reuses its blob.
+package attach
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/polylab/mypass-cli/internal/crypt"
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/store"
+)
+
+// DefaultMaxSize is the largest attachment by default, 10 MiB.
+const DefaultMaxSize = 10 << 20
+
+var (
+	ErrTooLarge  = errors.New("attachment too large")
+	ErrCorrupted = errors.New("attachment is corrupted")
+)
+
+type Option func(*Options)
+
+type Options struct {
+	maxSize int64
+}
+
+// WithMaxSize set the size of the largest attachment in bytes
+func WithMaxSize(n int64) Option {
+	return func(options *Options) {
+		options.maxSize = n
+	}
+}
+
+// Dir returns the attachment directory of the vault file.
+func Dir(file string) string {
+	return filepath.Join(filepath.Dir(file), "attachments")
+}
+
+type Attacher struct {
+	store *manager.Store
+	blobs store.BlobDir
+	opts  Options
+}
+
+func New(s *manager.Store, dir string, opts ...Option) *Attacher {
+	a := &Attacher{store: s, blobs: store.NewBlobDir(dir), opts: Options{maxSize: DefaultMaxSize}}
+	for _, o := range opts {
+		o(&a.opts)
+	}
+
+	return a
+}
+
+// Add attaches data as name to the entry with id.
+func (a *Attacher) Add(id, name string, data []byte) (manager.Attachment, error) {
+	if int64(len(data)) > a.opts.maxSize {
+		return manager.Attachment{}, fmt.Errorf("%s is %d bytes, the limit is %d: %w", name, len(data), a.opts.maxSize, ErrTooLarge)
+	}
+
+	sum := sha256.Sum256(data)
+	attachment, ok := a.store.FindAttachment(sum[:])
+	if !ok || !a.blobs.Has(attachment.Blob) {
+		key, err := crypt.NewBlobKey()
+		if err != nil {
+			return manager.Attachment{}, err
+		}
+
+		sealed, err := crypt.SealBlob(key, data)
+		if err != nil {
+			return manager.Attachment{}, fmt.Errorf("seal blob: %w", err)
+		}
+
+		blob, err := a.blobs.Put(sealed)
+		if err != nil {
+			return manager.Attachment{}, fmt.Errorf("put blob: %w", err)
+		}
+
+		attachment = manager.Attachment{Hash: sum[:], Key: key, Blob: blob}
+	}
+
+	attachment.Name, attachment.Size = name, int64(len(data))
+	if err := a.store.Attach(id, attachment); err != nil {
+		return manager.Attachment{}, fmt.Errorf("attach: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// Prune removes the blobs no tx of the vault references and returns how many were removed.
+// A blob of a removed attachment is referenced by the history of its entry until the log is compacted.
+func (a *Attacher) Prune() (int, error) {
+	names, err := a.blobs.Names()
+	if err != nil {
+		return 0, fmt.Errorf("list blobs: %w", err)
+	}
+
+	referenced := a.store.Blobs()
+	removed := 0
+	for _, name := range names {
+		if referenced[name] {
+			continue
+		}
+
+		if err = a.blobs.Remove(name); err != nil {
+			return removed, fmt.Errorf("remove blob: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Extract returns the content of the attachment.
+func (a *Attacher) Extract(attachment manager.Attachment) ([]byte, error) {
+	sealed, err := a.blobs.Get(attachment.Blob)
+	if err != nil {
+		return nil, fmt.Errorf("get blob: %w", err)
+	}
+
+	data, err := crypt.OpenBlob(attachment.Key, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("open blob: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], attachment.Hash) {
+		return nil, fmt.Errorf("%s: %w", attachment.Name, ErrCorrupted)
+	}
+
+	return data, nil
+}
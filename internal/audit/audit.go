@@ -0,0 +1,163 @@
+// Package audit checks the passwords of entries for weak, reused and stale ones.
+//
+// Reused passwords are found by comparing HMAC-SHA256 of the passwords under a random key of the audit,
+// the key is dropped with the audit so neither the passwords nor comparable hashes of them are kept.
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/strength"
+)
+
+const (
+	// DefaultMinScore is the lowest strength.Score of a password that is not weak.
+	DefaultMinScore = 3
+	// DefaultMaxAge is the age of a password after which it is stale, a year.
+	DefaultMaxAge = 365 * 24 * time.Hour
+)
+
+type Option func(*Options)
+
+type Options struct {
+	minScore int
+	maxAge   time.Duration
+	now      time.Time
+}
+
+// WithMinScore set the lowest score of a password that is not weak, 0 disables the check
+func WithMinScore(score int) Option {
+	return func(options *Options) {
+		options.minScore = score
+	}
+}
+
+// WithMaxAge set the age of a password after which it is stale, 0 disables the check
+func WithMaxAge(d time.Duration) Option {
+	return func(options *Options) {
+		options.maxAge = d
+	}
+}
+
+// WithNow set the time the ages of passwords are counted to
+func WithNow(t time.Time) Option {
+	return func(options *Options) {
+		options.now = t
+	}
+}
+
+// Ref refers to an audited entry.
+type Ref struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// Weak is an entry with a password scoring less than the min score.
+type Weak struct {
+	Ref
+	Score int `json:"score"`
+	// Entropy is log2 of the estimated guesses of the password.
+	Entropy float64 `json:"entropy"`
+	// Patterns are the patterns found in the password such as dictionary or spatial.
+	Patterns []string `json:"patterns"`
+}
+
+// Reused are the entries sharing a password.
+type Reused struct {
+	Entries []Ref `json:"entries"`
+}
+
+// Stale is an entry not updated for longer than the max age.
+type Stale struct {
+	Ref
+	UpdatedAt time.Time `json:"updated_at"`
+	AgeDays   int       `json:"age_days"`
+}
+
+// Report is the result of an audit, findings are in the order of entries.
+type Report struct {
+	Checked int      `json:"checked"`
+	Weak    []Weak   `json:"weak"`
+	Reused  []Reused `json:"reused"`
+	Stale   []Stale  `json:"stale"`
+}
+
+// Findings returns the number of weak, reused and stale findings.
+func (r Report) Findings() int {
+	return len(r.Weak) + len(r.Reused) + len(r.Stale)
+}
+
+// Audit checks the passwords of the entries, entries without a password are skipped. A password is weak
+// when its strength.Score is less than the min score with the title, username, URLs and folder of its entry
+// as user inputs. A password is stale when the entry was not updated for longer than the max age.
+func Audit(entries []manager.Entry, opts ...Option) (Report, error) {
+	options := Options{minScore: DefaultMinScore, maxAge: DefaultMaxAge, now: time.Now()}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return Report{}, fmt.Errorf("audit key: %w", err)
+	}
+
+	report := Report{Weak: []Weak{}, Reused: []Reused{}, Stale: []Stale{}}
+	groups := make(map[string]int)
+	var reused [][]Ref
+	for _, entry := range entries {
+		if entry.Password == "" {
+			continue
+		}
+
+		report.Checked++
+		ref := Ref{ID: entry.ID, Path: entry.Path()}
+
+		if options.minScore > 0 {
+			inputs := append([]string{entry.Title, entry.Username, entry.Folder}, entry.URLs...)
+			if result := strength.Estimate(entry.Password, inputs...); result.Score < options.minScore {
+				patterns := result.Patterns()
+				if patterns == nil {
+					patterns = []string{}
+				}
+
+				report.Weak = append(report.Weak, Weak{
+					Ref:      ref,
+					Score:    result.Score,
+					Entropy:  result.Entropy,
+					Patterns: patterns,
+				})
+			}
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(entry.Password))
+		sum := string(mac.Sum(nil))
+		if idx, ok := groups[sum]; ok {
+			reused[idx] = append(reused[idx], ref)
+		} else {
+			groups[sum] = len(reused)
+			reused = append(reused, []Ref{ref})
+		}
+
+		if age := options.now.Sub(entry.UpdatedAt); options.maxAge > 0 && age > options.maxAge {
+			report.Stale = append(report.Stale, Stale{
+				Ref:       ref,
+				UpdatedAt: entry.UpdatedAt,
+				AgeDays:   int(age / (24 * time.Hour)),
+			})
+		}
+	}
+
+	for _, refs := range reused {
+		if len(refs) > 1 {
+			report.Reused = append(report.Reused, Reused{Entries: refs})
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/polylab/mypass-cli/internal/manager"
+)
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fresh, old := now.Add(-24*time.Hour), now.Add(-400*24*time.Hour)
+	entries := []manager.Entry{
+		{ID: "1", Title: "github", Folder: "work", Password: "ZYl9&U#0){3c6cS&T4;N", UpdatedAt: fresh},
+		{ID: "2", Title: "mail", Password: "password", UpdatedAt: fresh},
+		{ID: "3", Title: "bank", Password: "ZYl9&U#0){3c6cS&T4;N", UpdatedAt: old},
+		{ID: "4", Title: "notes", UpdatedAt: old},
+		{ID: "5", Title: "shop", Password: "Shop2024", Username: "bob", UpdatedAt: fresh},
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected Report
+	}{
+		{
+			name: "test_audit_default",
+			opts: []Option{WithNow(now)},
+			expected: Report{
+				Checked: 4,
+				Weak: []Weak{
+					{Ref: Ref{ID: "2", Path: "mail"}, Score: 0, Patterns: []string{"dictionary"}},
+					{Ref: Ref{ID: "5", Path: "shop"}, Score: 1, Patterns: []string{"dictionary", "year"}},
+				},
+				Reused: []Reused{
+					{Entries: []Ref{{ID: "1", Path: "work/github"}, {ID: "3", Path: "bank"}}},
+				},
+				Stale: []Stale{
+					{Ref: Ref{ID: "3", Path: "bank"}, UpdatedAt: old, AgeDays: 400},
+				},
+			},
+		},
+		{
+			name: "test_audit_disabled_checks",
+			opts: []Option{WithNow(now), WithMinScore(0), WithMaxAge(0)},
+			expected: Report{
+				Checked: 4,
+				Weak:    []Weak{},
+				Reused: []Reused{
+					{Entries: []Ref{{ID: "1", Path: "work/github"}, {ID: "3", Path: "bank"}}},
+				},
+				Stale: []Stale{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := Audit(entries, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, report, cmp.FilterPath(func(p cmp.Path) bool {
+				return p.Last().String() == ".Entropy"
+			}, cmp.Ignore())); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReport_Findings(t *testing.T) {
+	t.Parallel()
+
+	report := Report{
+		Weak:   []Weak{{Ref: Ref{ID: "1"}}},
+		Reused: []Reused{{Entries: []Ref{{ID: "2"}, {ID: "3"}}}},
+	}
+
+	if diff := cmp.Diff(2, report.Findings()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
@@ -0,0 +1,321 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	CipherAEADAES256GCM byte = 0x2
+	CipherAEADXChaCha20 byte = 0x3
+)
+
+const (
+	encryptionKeyInfo   = "mypass encryption key"
+	verificationKeyInfo = "mypass verification key"
+)
+
+var ErrCryptFileCorrupted = errors.New("file is corrupted")
+
+type Option func(*Options)
+
+type Options struct {
+	kdf  KDFParams
+	salt []byte
+}
+
+// WithKDF set kdf parameters and salt the secret was derived with, they are stored in the header
+func WithKDF(params KDFParams, salt []byte) Option {
+	return func(options *Options) {
+		options.kdf = params
+		options.salt = salt
+	}
+}
+
+func NewAESGCM(secret []byte, store FS, opts ...Option) (CipherFS, error) {
+	return newAEADFS(CipherAEADAES256GCM, secret, newGCM, store, opts...)
+}
+
+func NewXChaCha20(secret []byte, store FS, opts ...Option) (CipherFS, error) {
+	return newAEADFS(CipherAEADXChaCha20, secret, newXChaCha20, store, opts...)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new AES cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+func newXChaCha20(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("new XChaCha20-Poly1305: %w", err)
+	}
+
+	return aead, nil
+}
+
+func newAEADFS(
+	typ byte, secret []byte, newFn func(key []byte) (cipher.AEAD, error), store FS, opts ...Option,
+) (CipherFS, error) {
+	encKey, macKey, err := splitKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("split key: %w", err)
+	}
+
+	aead, err := newFn(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	masterAEAD, err := newFn(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &aeadFS{
+		cipherTyp:  typ,
+		aead:       aead,
+		masterAEAD: masterAEAD,
+		macKey:     macKey,
+		sysFS:      store,
+		opts:       Options{kdf: PBKDF2Params()},
+	}
+
+	for _, o := range opts {
+		o(&c.opts)
+	}
+
+	return c, nil
+}
+
+// aeadFS stores data as a single authenticated container: header, ciphertext with the authentication tag.
+// The encoded header is bound to the ciphertext as additional data.
+//
+// The master key is split into an encryption key and a verification key, the header carries
+// a key check value computed with the verification key, so a wrong password is told apart from
+// a modified file. Files with a version 1 header or without a header were encrypted with the master key
+// and are still read.
+type aeadFS struct {
+	cipherTyp  byte
+	aead       cipher.AEAD
+	masterAEAD cipher.AEAD
+	macKey     []byte
+	sysFS      FS
+	opts       Options
+}
+
+func (c *aeadFS) Lock() (func() error, error) {
+	return lock(c.sysFS)
+}
+
+func (c *aeadFS) VerifyCipher() error {
+	src, err := c.sysFS.Open()
+	if err != nil {
+		return fmt.Errorf("sysFS open: %w", err)
+	}
+
+	if len(src) == 0 {
+		return ErrCryptFileEmpty
+	}
+
+	if IsHeader(src) {
+		header, _, err := ParseHeader(src)
+		if err != nil {
+			return fmt.Errorf("parse header: %w", err)
+		}
+
+		if header.Version >= HeaderVersion2 {
+			return c.verifyHeader(header)
+		}
+	}
+
+	if _, err = c.decrypt(src); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *aeadFS) Open() ([]byte, error) {
+	src, err := c.sysFS.Open()
+	if err != nil {
+		return nil, fmt.Errorf("sysFS open: %w", err)
+	}
+
+	dst, err := c.decrypt(src)
+	if err != nil {
+		if errors.Is(err, ErrCryptFileEmpty) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+
+	return dst, nil
+}
+
+func (c *aeadFS) Write(src []byte) error {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	header := Header{
+		Version: HeaderVersion2,
+		Cipher:  c.cipherTyp,
+		KDF:     c.opts.kdf,
+		Salt:    c.opts.salt,
+		Nonce:   nonce,
+	}
+	header.Check = keyCheck(c.macKey, header)
+
+	dst := header.Marshal()
+	dst = c.aead.Seal(dst, nonce, src, dst)
+
+	writer, ok := c.sysFS.(VerifyWriter)
+	if !ok {
+		if err := c.sysFS.Write(dst); err != nil {
+			return fmt.Errorf("write encrypted data: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := writer.WriteVerify(dst, func(b []byte) error {
+		written, err := c.decrypt(b)
+		if err != nil {
+			return fmt.Errorf("decrypt: %w", err)
+		}
+
+		if !bytes.Equal(written, src) {
+			return ErrWriteNotVerified
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("write encrypted data: %w", err)
+	}
+
+	return nil
+}
+
+func (c *aeadFS) decrypt(src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, ErrCryptFileEmpty
+	}
+
+	if !IsHeader(src) {
+		return c.decryptHeaderless(src)
+	}
+
+	header, size, err := ParseHeader(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse header: %w", err)
+	}
+
+	if header.Cipher != c.cipherTyp {
+		return nil, ErrCipherBlock
+	}
+
+	if header.Version == HeaderVersion1 {
+		return c.open(c.masterAEAD, header.Nonce, src[size:], src[:size], ErrSecretNotValid)
+	}
+
+	if err = c.verifyHeader(header); err != nil {
+		return nil, err
+	}
+
+	// The key is proven right, a failed authentication means the file was modified.
+	return c.open(c.aead, header.Nonce, src[size:], src[:size], ErrCryptFileCorrupted)
+}
+
+func (c *aeadFS) verifyHeader(header Header) error {
+	if header.Cipher != c.cipherTyp {
+		return ErrCipherBlock
+	}
+
+	if !hmac.Equal(keyCheck(c.macKey, header), header.Check) {
+		return ErrSecretNotValid
+	}
+
+	return nil
+}
+
+// open decrypts and authenticates ciphertext, authErr is returned when the authentication fails.
+func (c *aeadFS) open(aead cipher.AEAD, nonce, ciphertext, ad []byte, authErr error) ([]byte, error) {
+	if len(nonce) != aead.NonceSize() || len(ciphertext) < aead.Overhead() {
+		return nil, ErrCryptFileCorrupted
+	}
+
+	dst, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, authErr
+	}
+
+	return dst, nil
+}
+
+func (c *aeadFS) decryptHeaderless(src []byte) ([]byte, error) {
+	typ := src[0]
+	switch typ {
+	case CipherAEADAES256GCM, CipherAEADXChaCha20:
+	case CipherBlockAES, CipherBlockDES:
+		return nil, ErrCipherBlock
+	default:
+		return nil, ErrCipherBlockNotSupport
+	}
+
+	if typ != c.cipherTyp {
+		return nil, ErrCipherBlock
+	}
+
+	nonceSize := c.masterAEAD.NonceSize()
+	if len(src) < 1+nonceSize {
+		return nil, ErrCryptFileCorrupted
+	}
+
+	// Wrong key and modified ciphertext are indistinguishable here.
+	return c.open(c.masterAEAD, src[1:1+nonceSize], src[1+nonceSize:], src[:1], ErrSecretNotValid)
+}
+
+// splitKey derives independent encryption and verification keys from the master key with HKDF-SHA256.
+func splitKey(master []byte) ([]byte, []byte, error) {
+	encKey := make([]byte, len(master))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, []byte(encryptionKeyInfo)), encKey); err != nil {
+		return nil, nil, fmt.Errorf("derive encryption key: %w", err)
+	}
+
+	macKey := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, []byte(verificationKeyInfo)), macKey); err != nil {
+		return nil, nil, fmt.Errorf("derive verification key: %w", err)
+	}
+
+	return encKey, macKey, nil
+}
+
+// keyCheck returns HMAC-SHA256 of the header fields under the verification key.
+func keyCheck(macKey []byte, header Header) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(header.checkData())
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,302 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type memFS struct {
+	b []byte
+}
+
+func (m *memFS) Open() ([]byte, error) {
+	return m.b, nil
+}
+
+func (m *memFS) Write(b []byte) error {
+	m.b = append([]byte(nil), b...)
+
+	return nil
+}
+
+func testKey(t *testing.T, password string) []byte {
+	t.Helper()
+
+	key, err := GeneratePrivateKeyAES()(password)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return key
+}
+
+func TestAEAD_Open(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		newFn  func(secret []byte, store FS, opts ...Option) (CipherFS, error)
+		data   []byte
+		modify func(b []byte) []byte
+		err    error
+	}{
+		{
+			name:  "test_aes_gcm_round_trip",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+		},
+		{
+			name:  "test_xchacha20_round_trip",
+			newFn: NewXChaCha20,
+			data:  []byte("secret data"),
+		},
+		{
+			name:  "test_aes_gcm_tampered",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				b[len(b)-1] ^= 0xff
+
+				return b
+			},
+			err: ErrCryptFileCorrupted,
+		},
+		{
+			name:  "test_xchacha20_truncated",
+			newFn: NewXChaCha20,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				return b[:len(b)-5]
+			},
+			err: ErrCryptFileCorrupted,
+		},
+		{
+			name:  "test_aes_gcm_tampered_kdf",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				b[10] ^= 0xff
+
+				return b
+			},
+			err: ErrSecretNotValid,
+		},
+		{
+			name:  "test_aes_gcm_tampered_kdf_over_limit",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				b[7] ^= 0xff
+
+				return b
+			},
+			err: ErrKDFParamsNotValid,
+		},
+		{
+			name:  "test_aes_gcm_truncated_header",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				return b[:10]
+			},
+			err: ErrHeaderNotValid,
+		},
+		{
+			name:  "test_aes_gcm_cipher_mismatch",
+			newFn: NewAESGCM,
+			data:  []byte("secret data"),
+			modify: func(b []byte) []byte {
+				b[5] = CipherAEADXChaCha20
+
+				return b
+			},
+			err: ErrCipherBlock,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sysFS := &memFS{}
+			fs, err := tc.newFn(testKey(t, "password"), sysFS)
+			if err != nil {
+				t.Fatalf("new cipher fs: %v", err)
+			}
+
+			if err = fs.Write(tc.data); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			if tc.modify != nil {
+				sysFS.b = tc.modify(sysFS.b)
+			}
+
+			got, err := fs.Open()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("open: got %v, want %v", err, tc.err)
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.data, got); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAEAD_OpenHeaderless(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t, "password")
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("new gcm: %v", err)
+	}
+
+	data := []byte("secret data")
+	nonce := make([]byte, aead.NonceSize())
+	src := append([]byte{CipherAEADAES256GCM}, nonce...)
+	src = aead.Seal(src, nonce, data, src[:1])
+
+	fs, err := NewAESGCM(key, &memFS{b: src})
+	if err != nil {
+		t.Fatalf("new cipher fs: %v", err)
+	}
+
+	got, err := fs.Open()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	if diff := cmp.Diff(data, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestAEAD_OpenHeaderV1(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t, "password")
+	aead, err := newGCM(key)
+	if err != nil {
+		t.Fatalf("new gcm: %v", err)
+	}
+
+	data := []byte("secret data")
+	header := Header{
+		Version: HeaderVersion1,
+		Cipher:  CipherAEADAES256GCM,
+		KDF:     PBKDF2Params(),
+		Nonce:   make([]byte, aead.NonceSize()),
+	}
+	src := header.Marshal()
+	src = aead.Seal(src, header.Nonce, data, src)
+
+	fs, err := NewAESGCM(key, &memFS{b: src})
+	if err != nil {
+		t.Fatalf("new cipher fs: %v", err)
+	}
+
+	if err = fs.VerifyCipher(); err != nil {
+		t.Fatalf("verify cipher: %v", err)
+	}
+
+	got, err := fs.Open()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	if diff := cmp.Diff(data, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestAEAD_WrongSecret(t *testing.T) {
+	t.Parallel()
+
+	sysFS := &memFS{}
+	fs, err := NewAESGCM(testKey(t, "password"), sysFS)
+	if err != nil {
+		t.Fatalf("new cipher fs: %v", err)
+	}
+
+	if err = fs.Write([]byte("secret data")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	fs, err = NewAESGCM(testKey(t, "password1"), sysFS)
+	if err != nil {
+		t.Fatalf("new cipher fs: %v", err)
+	}
+
+	if err = fs.VerifyCipher(); !errors.Is(err, ErrSecretNotValid) {
+		t.Errorf("verify cipher: got %v, want %v", err, ErrSecretNotValid)
+	}
+}
+
+func TestMigrateFS(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t, "password")
+	sysFS := &memFS{}
+	legacy, err := NewAES(key, sysFS)
+	if err != nil {
+		t.Fatalf("new legacy fs: %v", err)
+	}
+
+	data := []byte("secret data 0123456789")
+	if err = legacy.Write(data); err != nil {
+		t.Fatalf("legacy write: %v", err)
+	}
+
+	aead, err := NewAESGCM(key, sysFS)
+	if err != nil {
+		t.Fatalf("new aead fs: %v", err)
+	}
+
+	fs := NewMigrateFS(legacy, aead)
+	if err = fs.VerifyCipher(); err != nil {
+		t.Fatalf("verify legacy: %v", err)
+	}
+
+	got, err := fs.Open()
+	if err != nil {
+		t.Fatalf("open legacy: %v", err)
+	}
+
+	// legacy block format pads the last block with zeros
+	if diff := cmp.Diff(data, got[:len(data)]); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = fs.Write(data); err != nil {
+		t.Fatalf("migrate write: %v", err)
+	}
+
+	if diff := cmp.Diff(true, IsHeader(sysFS.b)); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	got, err = fs.Open()
+	if err != nil {
+		t.Fatalf("open migrated: %v", err)
+	}
+
+	if diff := cmp.Diff(data, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
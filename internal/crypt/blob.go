@@ -0,0 +1,71 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// blobMagic opens every sealed blob, it is followed by the version byte.
+var blobMagic = []byte("MPBL")
+
+const blobVersion1 byte = 0x1
+
+// NewBlobKey returns a random key for SealBlob.
+func NewBlobKey() ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate blob key: %w", err)
+	}
+
+	return key, nil
+}
+
+// SealBlob encrypts data with the key using XChaCha20-Poly1305.
+//
+// Layout: magic(4) | version(1) | nonce(24) | ciphertext with the authentication tag.
+// Magic and version are bound to the ciphertext as additional data.
+func SealBlob(key, data []byte) ([]byte, error) {
+	aead, err := newXChaCha20(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	dst := append(append([]byte(nil), blobMagic...), blobVersion1)
+	ad := dst[:len(dst):len(dst)]
+	dst = append(dst, nonce...)
+
+	return aead.Seal(dst, nonce, data, ad), nil
+}
+
+// OpenBlob decrypts and authenticates the blob sealed by SealBlob.
+func OpenBlob(key, blob []byte) ([]byte, error) {
+	aead, err := newXChaCha20(key)
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(blobMagic) + 1
+	if len(blob) < size+aead.NonceSize()+aead.Overhead() || !bytes.HasPrefix(blob, blobMagic) {
+		return nil, ErrCryptFileCorrupted
+	}
+
+	if blob[len(blobMagic)] != blobVersion1 {
+		return nil, ErrHeaderVersionNotSupport
+	}
+
+	nonce := blob[size : size+aead.NonceSize()]
+	data, err := aead.Open(nil, nonce, blob[size+aead.NonceSize():], blob[:size])
+	if err != nil {
+		return nil, ErrCryptFileCorrupted
+	}
+
+	return data, nil
+}
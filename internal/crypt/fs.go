@@ -4,9 +4,9 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/des"
+	"crypto/subtle"
 	"errors"
 	"fmt"
-	"reflect"
 )
 
 type encodingFunc = func([]byte) []byte
@@ -21,6 +21,18 @@ type CipherFS interface {
 	VerifyCipher() error
 }
 
+// Locker is implemented by FS that can lock the stored data for a read-modify-write cycle.
+type Locker interface {
+	Lock() (unlock func() error, err error)
+}
+
+// VerifyWriter is implemented by FS that hand the written data back to verify before it replaces the stored one.
+type VerifyWriter interface {
+	WriteVerify(b []byte, verify func(b []byte) error) error
+}
+
+// NewAES returns CipherFS for legacy vaults encrypted block by block with AES.
+// It is kept to read existing vaults, new data is written with NewAESGCM or NewXChaCha20.
 func NewAES(secret []byte, store FS) (CipherFS, error) {
 	block, err := aes.NewCipher(secret)
 	if err != nil {
@@ -34,6 +46,8 @@ func NewAES(secret []byte, store FS) (CipherFS, error) {
 	}, nil
 }
 
+// NewDES returns CipherFS for legacy vaults encrypted block by block with DES.
+// It is kept to read existing vaults, new data is written with NewAESGCM or NewXChaCha20.
 func NewDES(secret []byte, store FS) (CipherFS, error) {
 	block, err := des.NewCipher(secret)
 	if err != nil {
@@ -59,6 +73,7 @@ var (
 	ErrCipherBlockNotSupport = errors.New("cipher block not support")
 	ErrCryptFileEmpty        = errors.New("file is empty")
 	ErrSecretNotValid        = errors.New("secret not valid")
+	ErrWriteNotVerified      = errors.New("written data not verified")
 )
 
 type fs struct {
@@ -67,6 +82,10 @@ type fs struct {
 	sysFS     FS
 }
 
+func (c *fs) Lock() (func() error, error) {
+	return lock(c.sysFS)
+}
+
 func (c *fs) VerifyCipher() error {
 	return c.verifyCipher()
 }
@@ -137,7 +156,7 @@ func (c *fs) verifyCipher() error {
 
 	localEncTestPhrase := c.read([]byte(phraseValidator), c.encrypt)
 	diskEncTestPhrase := src[1 : size+1]
-	if !reflect.DeepEqual(localEncTestPhrase, diskEncTestPhrase) {
+	if subtle.ConstantTimeCompare(localEncTestPhrase, diskEncTestPhrase) != 1 {
 		return ErrSecretNotValid
 	}
 
@@ -185,3 +204,12 @@ func (c *fs) decrypt(src []byte) []byte {
 
 	return dst
 }
+
+// lock takes the lock of store if it supports locking.
+func lock(store FS) (func() error, error) {
+	if l, ok := store.(Locker); ok {
+		return l.Lock()
+	}
+
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,180 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	HeaderVersion1 byte = 0x1
+	HeaderVersion2 byte = 0x2
+)
+
+const (
+	KDFPBKDF2SHA512 byte = 0x1
+	KDFArgon2id     byte = 0x2
+	KDFScrypt       byte = 0x3
+)
+
+// headerMagic opens every vault file written with a header.
+// Its first byte never clashes with the cipher type byte of the headerless layouts.
+var headerMagic = []byte("MYPS")
+
+var (
+	ErrHeaderNotValid          = errors.New("header not valid")
+	ErrHeaderVersionNotSupport = errors.New("header version not support")
+)
+
+// KDFParams describes how the vault key is derived from the master password.
+// For PBKDF2 Time is the number of iterations.
+// For Argon2id Time, Memory (KiB) and Threads are the argon2 time, memory and parallelism costs.
+// For scrypt Time is the CPU/memory cost N, Memory is the block size r and Threads is the parallelization p.
+type KDFParams struct {
+	ID      byte
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// Header is the self-describing prefix of a vault file.
+//
+// Layout: magic(4) | version(1) | cipher(1) | kdf id(1) | kdf time(4) | kdf memory(4) | kdf threads(1) |
+// salt len(1) | salt | nonce len(1) | nonce, since version 2 followed by check len(1) | check.
+// Integers are big endian. The encoded header is authenticated as additional data of the AEAD.
+// Check is the key check value, it proves the key is right without decrypting the payload.
+type Header struct {
+	Version byte
+	Cipher  byte
+	KDF     KDFParams
+	Salt    []byte
+	Nonce   []byte
+	Check   []byte
+}
+
+// IsHeader reports whether b starts with the vault header magic.
+func IsHeader(b []byte) bool {
+	return bytes.HasPrefix(b, headerMagic)
+}
+
+// ParseHeader decodes the header at the beginning of b and returns it with its encoded size.
+// The kdf params are validated so a corrupted header fails before the key derivation.
+func ParseHeader(b []byte) (Header, int, error) {
+	var h Header
+
+	if !IsHeader(b) {
+		return h, 0, ErrHeaderNotValid
+	}
+
+	r := bytes.NewReader(b[len(headerMagic):])
+
+	var fixed struct {
+		Version byte
+		Cipher  byte
+		KDFID   byte
+		Time    uint32
+		Memory  uint32
+		Threads uint8
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &fixed); err != nil {
+		return h, 0, fmt.Errorf("read header: %w", ErrHeaderNotValid)
+	}
+
+	if fixed.Version != HeaderVersion1 && fixed.Version != HeaderVersion2 {
+		return h, 0, ErrHeaderVersionNotSupport
+	}
+
+	salt, err := readBytes(r)
+	if err != nil {
+		return h, 0, fmt.Errorf("read salt: %w", err)
+	}
+
+	nonce, err := readBytes(r)
+	if err != nil {
+		return h, 0, fmt.Errorf("read nonce: %w", err)
+	}
+
+	var check []byte
+	if fixed.Version >= HeaderVersion2 {
+		if check, err = readBytes(r); err != nil {
+			return h, 0, fmt.Errorf("read check: %w", err)
+		}
+	}
+
+	h = Header{
+		Version: fixed.Version,
+		Cipher:  fixed.Cipher,
+		KDF: KDFParams{
+			ID:      fixed.KDFID,
+			Time:    fixed.Time,
+			Memory:  fixed.Memory,
+			Threads: fixed.Threads,
+		},
+		Salt:  salt,
+		Nonce: nonce,
+		Check: check,
+	}
+
+	if err = h.KDF.Validate(); err != nil {
+		return Header{}, 0, fmt.Errorf("read kdf params: %w", err)
+	}
+
+	return h, len(b) - r.Len(), nil
+}
+
+// Marshal encodes the header.
+func (h Header) Marshal() []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, 64+len(h.Salt)+len(h.Nonce)+len(h.Check)))
+	buf.Write(h.checkData())
+	buf.WriteByte(byte(len(h.Nonce)))
+	buf.Write(h.Nonce)
+
+	if h.Version >= HeaderVersion2 {
+		buf.WriteByte(byte(len(h.Check)))
+		buf.Write(h.Check)
+	}
+
+	return buf.Bytes()
+}
+
+// checkData returns the encoded header fields the key check value is computed over.
+func (h Header) checkData() []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, 32+len(h.Salt)))
+	buf.Write(headerMagic)
+	buf.WriteByte(h.Version)
+	buf.WriteByte(h.Cipher)
+	buf.WriteByte(h.KDF.ID)
+
+	u32 := make([]byte, 4)
+	binary.BigEndian.PutUint32(u32, h.KDF.Time)
+	buf.Write(u32)
+	binary.BigEndian.PutUint32(u32, h.KDF.Memory)
+	buf.Write(u32)
+	buf.WriteByte(h.KDF.Threads)
+
+	buf.WriteByte(byte(len(h.Salt)))
+	buf.Write(h.Salt)
+
+	return buf.Bytes()
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	size, err := r.ReadByte()
+	if err != nil {
+		return nil, ErrHeaderNotValid
+	}
+
+	if int(size) > r.Len() {
+		return nil, ErrHeaderNotValid
+	}
+
+	b := make([]byte, size)
+	if _, err = io.ReadFull(r, b); err != nil {
+		return nil, ErrHeaderNotValid
+	}
+
+	return b, nil
+}
@@ -0,0 +1,98 @@
+package crypt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHeader_Marshal(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		header Header
+	}{
+		{
+			name: "test_header_0",
+			header: Header{
+				Version: HeaderVersion1,
+				Cipher:  CipherAEADAES256GCM,
+				KDF:     PBKDF2Params(),
+				Salt:    []byte{},
+				Nonce:   []byte("0123456789ab"),
+			},
+		},
+		{
+			name: "test_header_1",
+			header: Header{
+				Version: HeaderVersion1,
+				Cipher:  CipherAEADXChaCha20,
+				KDF:     KDFParams{ID: KDFPBKDF2SHA512, Time: 1, Memory: 2, Threads: 3},
+				Salt:    []byte("salt salt salt salt"),
+				Nonce:   []byte("0123456789abcdef01234567"),
+			},
+		},
+		{
+			name: "test_header_2",
+			header: Header{
+				Version: HeaderVersion2,
+				Cipher:  CipherAEADAES256GCM,
+				KDF:     Argon2idParams(),
+				Salt:    []byte("salt salt salt salt"),
+				Nonce:   []byte("0123456789ab"),
+				Check:   []byte("check check check check check 01"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := tc.header.Marshal()
+			b = append(b, "payload"...)
+
+			header, size, err := ParseHeader(b)
+			if err != nil {
+				t.Fatalf("parse header: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.header, header); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			if diff := cmp.Diff("payload", string(b[size:])); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseHeader_NotValid(t *testing.T) {
+	t.Parallel()
+
+	b := Header{Version: HeaderVersion2, Salt: []byte("salt"), Nonce: []byte("nonce"), Check: []byte("check")}.Marshal()
+	for i := 0; i < len(b); i++ {
+		if _, _, err := ParseHeader(b[:i]); !errors.Is(err, ErrHeaderNotValid) {
+			t.Errorf("parse header of %d bytes: got %v, want %v", i, err, ErrHeaderNotValid)
+		}
+	}
+
+	b[len(headerMagic)] = 0xff
+	if _, _, err := ParseHeader(b); !errors.Is(err, ErrHeaderVersionNotSupport) {
+		t.Errorf("parse header: got %v, want %v", err, ErrHeaderVersionNotSupport)
+	}
+}
+
+func TestParseHeader_KDFParamsNotValid(t *testing.T) {
+	t.Parallel()
+
+	h := Header{Version: HeaderVersion2, KDF: Argon2idParams(), Salt: []byte("salt"), Nonce: []byte("nonce")}
+	h.KDF.Memory = 1<<32 - 1
+	if _, _, err := ParseHeader(h.Marshal()); !errors.Is(err, ErrKDFParamsNotValid) {
+		t.Errorf("parse header: got %v, want %v", err, ErrKDFParamsNotValid)
+	}
+}
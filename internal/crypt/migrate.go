@@ -0,0 +1,55 @@
+package crypt
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NewMigrateFS returns CipherFS that reads with the from cipher and writes with the to cipher.
+// After the first successful write the file is in the new format and all reads go through to.
+func NewMigrateFS(from, to CipherFS) CipherFS {
+	return &migrateFS{from: from, to: to}
+}
+
+type migrateFS struct {
+	mtx      sync.RWMutex
+	from     CipherFS
+	to       CipherFS
+	migrated bool
+}
+
+func (m *migrateFS) Lock() (func() error, error) {
+	return lock(m.to)
+}
+
+func (m *migrateFS) VerifyCipher() error {
+	return m.current().VerifyCipher()
+}
+
+func (m *migrateFS) Open() ([]byte, error) {
+	return m.current().Open()
+}
+
+func (m *migrateFS) Write(b []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err := m.to.Write(b); err != nil {
+		return fmt.Errorf("migrate write: %w", err)
+	}
+
+	m.migrated = true
+
+	return nil
+}
+
+func (m *migrateFS) current() CipherFS {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if m.migrated {
+		return m.to
+	}
+
+	return m.from
+}
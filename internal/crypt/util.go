@@ -1,18 +1,40 @@
 package crypt
 
 import (
+	"crypto/rand"
 	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
 )
 
-func GeneratePrivateKeyFromPassword(keyLen int) func(password string) ([]byte, error) {
-	return func(password string) ([]byte, error) {
-		hash := sha512.New()
-		hash.Write([]byte(password))
+const (
+	pbkdf2Iterations = 4096
+	saltLen          = 16
+)
 
-		key := pbkdf2.Key([]byte(password), hash.Sum(nil), 4096, keyLen, sha512.New)
+// Upper limits of the kdf params, a corrupted or crafted header must not make the key derivation
+// run out of memory or take forever.
+const (
+	maxKDFMemory       = 1 << 30 // bytes
+	maxPBKDF2Time      = 1 << 24
+	maxArgon2idTime    = 1 << 10
+	maxArgon2idThreads = 64
+	maxScryptR         = 1 << 10
+	maxScryptP         = 64
+)
 
-		return key, nil
+var (
+	ErrKDFNotSupport     = errors.New("key derivation function not support")
+	ErrKDFParamsNotValid = errors.New("key derivation params not valid")
+)
+
+func GeneratePrivateKeyFromPassword(keyLen int) func(password string) ([]byte, error) {
+	return func(password string) ([]byte, error) {
+		return pbkdf2SHA512(password, nil, pbkdf2Iterations, keyLen), nil
 	}
 }
 
@@ -23,3 +45,109 @@ func GeneratePrivateKeyAES() func(password string) ([]byte, error) {
 func GeneratePrivateKeyDES() func(password string) ([]byte, error) {
 	return GeneratePrivateKeyFromPassword(8)
 }
+
+// PBKDF2Params returns parameters of the key derivation used by vaults without a header.
+func PBKDF2Params() KDFParams {
+	return KDFParams{ID: KDFPBKDF2SHA512, Time: pbkdf2Iterations}
+}
+
+// Argon2idParams returns the default Argon2id parameters: 3 passes over 64 MiB with 4 threads.
+func Argon2idParams() KDFParams {
+	return KDFParams{ID: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+// ScryptParams returns the default scrypt parameters: N=32768, r=8, p=1.
+func ScryptParams() KDFParams {
+	return KDFParams{ID: KDFScrypt, Time: 1 << 15, Memory: 8, Threads: 1}
+}
+
+// NewSalt returns a random salt for the key derivation.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	return salt, nil
+}
+
+// Validate checks the params are within the limits of their kdf.
+func (p KDFParams) Validate() error {
+	switch p.ID {
+	case KDFPBKDF2SHA512:
+		if p.Time == 0 || p.Time > maxPBKDF2Time {
+			return fmt.Errorf("pbkdf2 iterations %d: %w", p.Time, ErrKDFParamsNotValid)
+		}
+	case KDFArgon2id:
+		if p.Time == 0 || p.Time > maxArgon2idTime {
+			return fmt.Errorf("argon2id time %d: %w", p.Time, ErrKDFParamsNotValid)
+		}
+
+		if p.Memory == 0 || uint64(p.Memory)*1024 > maxKDFMemory {
+			return fmt.Errorf("argon2id memory %d KiB: %w", p.Memory, ErrKDFParamsNotValid)
+		}
+
+		if p.Threads == 0 || p.Threads > maxArgon2idThreads {
+			return fmt.Errorf("argon2id threads %d: %w", p.Threads, ErrKDFParamsNotValid)
+		}
+	case KDFScrypt:
+		if p.Time < 2 || p.Time&(p.Time-1) != 0 {
+			return fmt.Errorf("scrypt N %d is not a power of 2: %w", p.Time, ErrKDFParamsNotValid)
+		}
+
+		if p.Memory == 0 || p.Memory > maxScryptR || 128*uint64(p.Time)*uint64(p.Memory) > maxKDFMemory {
+			return fmt.Errorf("scrypt N %d, r %d: %w", p.Time, p.Memory, ErrKDFParamsNotValid)
+		}
+
+		if p.Threads == 0 || p.Threads > maxScryptP {
+			return fmt.Errorf("scrypt p %d: %w", p.Threads, ErrKDFParamsNotValid)
+		}
+	default:
+		return ErrKDFNotSupport
+	}
+
+	return nil
+}
+
+// DeriveKey derives a key of keyLen bytes from the password with the kdf described by params.
+func DeriveKey(password string, params KDFParams, salt []byte, keyLen int) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch params.ID {
+	case KDFPBKDF2SHA512:
+		return pbkdf2SHA512(password, salt, int(params.Time), keyLen), nil
+	case KDFArgon2id:
+		if len(salt) == 0 {
+			return nil, ErrKDFParamsNotValid
+		}
+
+		return argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(keyLen)), nil
+	case KDFScrypt:
+		if len(salt) == 0 {
+			return nil, ErrKDFParamsNotValid
+		}
+
+		key, err := scrypt.Key([]byte(password), salt, int(params.Time), int(params.Memory), int(params.Threads), keyLen)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt: %v: %w", err, ErrKDFParamsNotValid)
+		}
+
+		return key, nil
+	default:
+		return nil, ErrKDFNotSupport
+	}
+}
+
+// pbkdf2SHA512 falls back to the salt derived from the password itself when salt is empty,
+// as the vaults without a header did.
+func pbkdf2SHA512(password string, salt []byte, iter, keyLen int) []byte {
+	if len(salt) == 0 {
+		hash := sha512.New()
+		hash.Write([]byte(password))
+		salt = hash.Sum(nil)
+	}
+
+	return pbkdf2.Key([]byte(password), salt, iter, keyLen, sha512.New)
+}
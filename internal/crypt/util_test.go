@@ -0,0 +1,153 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		params KDFParams
+	}{
+		{
+			name:   "test_pbkdf2",
+			params: PBKDF2Params(),
+		},
+		{
+			name:   "test_argon2id",
+			params: KDFParams{ID: KDFArgon2id, Time: 1, Memory: 1024, Threads: 1},
+		},
+		{
+			name:   "test_scrypt",
+			params: KDFParams{ID: KDFScrypt, Time: 1 << 10, Memory: 8, Threads: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			salt0, err := NewSalt()
+			if err != nil {
+				t.Fatalf("new salt: %v", err)
+			}
+
+			salt1, err := NewSalt()
+			if err != nil {
+				t.Fatalf("new salt: %v", err)
+			}
+
+			key0, err := DeriveKey("password", tc.params, salt0, 32)
+			if err != nil {
+				t.Fatalf("derive key: %v", err)
+			}
+
+			key1, err := DeriveKey("password", tc.params, salt0, 32)
+			if err != nil {
+				t.Fatalf("derive key: %v", err)
+			}
+
+			key2, err := DeriveKey("password", tc.params, salt1, 32)
+			if err != nil {
+				t.Fatalf("derive key: %v", err)
+			}
+
+			if len(key0) != 32 {
+				t.Errorf("key length: got %d, want 32", len(key0))
+			}
+
+			if !bytes.Equal(key0, key1) {
+				t.Error("same password and salt derived different keys")
+			}
+
+			if bytes.Equal(key0, key2) {
+				t.Error("different salts derived the same key")
+			}
+		})
+	}
+}
+
+func TestDeriveKey_NotValid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		params KDFParams
+		salt   []byte
+		err    error
+	}{
+		{
+			name:   "test_argon2id_without_salt",
+			params: Argon2idParams(),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_argon2id_zero_threads",
+			params: KDFParams{ID: KDFArgon2id, Time: 1, Memory: 1024},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_scrypt_bad_n",
+			params: KDFParams{ID: KDFScrypt, Time: 1000, Memory: 8, Threads: 1},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_pbkdf2_too_many_iterations",
+			params: KDFParams{ID: KDFPBKDF2SHA512, Time: 1 << 30},
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_argon2id_too_much_memory",
+			params: KDFParams{ID: KDFArgon2id, Time: 1, Memory: 1 << 31, Threads: 1},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_argon2id_too_many_passes",
+			params: KDFParams{ID: KDFArgon2id, Time: 1 << 20, Memory: 1024, Threads: 1},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_argon2id_too_many_threads",
+			params: KDFParams{ID: KDFArgon2id, Time: 1, Memory: 1024, Threads: 255},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_scrypt_too_much_memory",
+			params: KDFParams{ID: KDFScrypt, Time: 1 << 24, Memory: 8, Threads: 1},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_scrypt_too_many_threads",
+			params: KDFParams{ID: KDFScrypt, Time: 1 << 10, Memory: 8, Threads: 255},
+			salt:   []byte("salt"),
+			err:    ErrKDFParamsNotValid,
+		},
+		{
+			name:   "test_unknown",
+			params: KDFParams{ID: 0xff},
+			err:    ErrKDFNotSupport,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := DeriveKey("password", tc.params, tc.salt, 32); !errors.Is(err, tc.err) {
+				t.Errorf("derive key: got %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Package generate generates random passwords and diceware passphrases with crypto/rand.
+package generate
+
+import (
+	"bufio"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+	// ambiguousChars are easy to confuse with each other when read or typed
+	ambiguousChars = "Il1|O0o"
+)
+
+var ErrPolicyNotValid = errors.New("password policy not valid")
+
+// wordlist is the EFF large wordlist of 7776 words for passphrases of five dice rolls per word,
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases, licensed under CC BY 3.0 US.
+//
+//go:embed eff_large_wordlist.txt
+var wordlist string
+
+var (
+	wordsOnce sync.Once
+	words     []string
+)
+
+// Policy describes the passwords to generate. A policy with Words generates diceware passphrases
+// of Words words joined by Separator, otherwise passwords of Length characters of the enabled classes.
+type Policy struct {
+	Length  int
+	Lower   bool
+	Upper   bool
+	Digits  bool
+	Symbols bool
+	// ExcludeAmbiguous drops characters that are easy to confuse such as l, 1, O and 0
+	ExcludeAmbiguous bool
+	// Require makes every password contain at least one character of every enabled class
+	Require bool
+
+	Words     int
+	Separator string
+}
+
+// DefaultPolicy returns the policy of 20 characters of all classes with at least one of every class.
+func DefaultPolicy() Policy {
+	return Policy{Length: 20, Lower: true, Upper: true, Digits: true, Symbols: true, Require: true}
+}
+
+// WithLength returns the policy of passwords of n characters. A policy without character classes,
+// such as a passphrase policy, gets the classes of DefaultPolicy.
+func (p Policy) WithLength(n int) Policy {
+	p.Length, p.Words = n, 0
+	if len(p.classes()) == 0 {
+		d := DefaultPolicy()
+		p.Lower, p.Upper, p.Digits, p.Symbols, p.Require = d.Lower, d.Upper, d.Digits, d.Symbols, d.Require
+	}
+
+	return p
+}
+
+// Generate returns a password or a passphrase of the policy.
+func Generate(p Policy) (string, error) {
+	if p.Words > 0 {
+		return passphrase(p.Words, p.Separator)
+	}
+
+	return password(p)
+}
+
+// Entropy returns the bits of entropy of a password or a passphrase of the policy
+// without the reduction of Require.
+func Entropy(p Policy) float64 {
+	if p.Words > 0 {
+		return float64(p.Words) * math.Log2(float64(len(wordList())))
+	}
+
+	alphabet := strings.Join(p.classes(), "")
+	if alphabet == "" {
+		return 0
+	}
+
+	return float64(p.Length) * math.Log2(float64(len(alphabet)))
+}
+
+func (p Policy) classes() []string {
+	var classes []string
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{p.Lower, lowerChars},
+		{p.Upper, upperChars},
+		{p.Digits, digitChars},
+		{p.Symbols, symbolChars},
+	} {
+		if !class.enabled {
+			continue
+		}
+
+		chars := class.chars
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+
+				return r
+			}, chars)
+		}
+
+		classes = append(classes, chars)
+	}
+
+	return classes
+}
+
+func password(p Policy) (string, error) {
+	classes := p.classes()
+	if len(classes) == 0 {
+		return "", fmt.Errorf("%w: no character classes", ErrPolicyNotValid)
+	}
+
+	if p.Length <= 0 || p.Require && p.Length < len(classes) {
+		return "", fmt.Errorf("%w: length %d for %d required classes", ErrPolicyNotValid, p.Length, len(classes))
+	}
+
+	alphabet := strings.Join(classes, "")
+	b := make([]byte, 0, p.Length)
+	if p.Require {
+		for _, class := range classes {
+			c, err := pick(class)
+			if err != nil {
+				return "", err
+			}
+
+			b = append(b, c)
+		}
+	}
+
+	for len(b) < p.Length {
+		c, err := pick(alphabet)
+		if err != nil {
+			return "", err
+		}
+
+		b = append(b, c)
+	}
+
+	// the required characters are shuffled away from the start
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return string(b), nil
+}
+
+func passphrase(n int, separator string) (string, error) {
+	list := wordList()
+	picked := make([]string, n)
+	for idx := range picked {
+		i, err := randInt(len(list))
+		if err != nil {
+			return "", err
+		}
+
+		picked[idx] = list[i]
+	}
+
+	return strings.Join(picked, separator), nil
+}
+
+// wordList returns the words of the embedded wordlist, every line is the dice rolls and the word.
+func wordList() []string {
+	wordsOnce.Do(func() {
+		scanner := bufio.NewScanner(strings.NewReader(wordlist))
+		for scanner.Scan() {
+			if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+				words = append(words, fields[1])
+			}
+		}
+	})
+
+	return words
+}
+
+func pick(chars string) (byte, error) {
+	i, err := randInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+
+	return chars[i], nil
+}
+
+// randInt returns a uniform random number in [0, n).
+func randInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("random: %w", err)
+	}
+
+	return int(i.Int64()), nil
+}
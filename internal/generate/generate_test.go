@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		policy  Policy
+		allowed string
+		classes []string
+		err     error
+	}{
+		{
+			name:    "test_generate_default",
+			policy:  DefaultPolicy(),
+			allowed: lowerChars + upperChars + digitChars + symbolChars,
+			classes: []string{lowerChars, upperChars, digitChars, symbolChars},
+		},
+		{
+			name:    "test_generate_digits",
+			policy:  Policy{Length: 6, Digits: true},
+			allowed: digitChars,
+		},
+		{
+			name:    "test_generate_required_short",
+			policy:  Policy{Length: 4, Lower: true, Upper: true, Digits: true, Symbols: true, Require: true},
+			allowed: lowerChars + upperChars + digitChars + symbolChars,
+			classes: []string{lowerChars, upperChars, digitChars, symbolChars},
+		},
+		{
+			name:    "test_generate_exclude_ambiguous",
+			policy:  Policy{Length: 64, Lower: true, Upper: true, Digits: true, ExcludeAmbiguous: true},
+			allowed: "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789",
+		},
+		{
+			name:   "test_generate_no_classes",
+			policy: Policy{Length: 8},
+			err:    ErrPolicyNotValid,
+		},
+		{
+			name:   "test_generate_too_short",
+			policy: Policy{Length: 2, Lower: true, Upper: true, Digits: true, Require: true},
+			err:    ErrPolicyNotValid,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 100; i++ {
+				password, err := Generate(tc.policy)
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("generate: %v, want %v", err, tc.err)
+				}
+
+				if tc.err != nil {
+					return
+				}
+
+				if diff := cmp.Diff(tc.policy.Length, len(password)); diff != "" {
+					t.Fatalf("diff (+got, -want): %s", diff)
+				}
+
+				if i := strings.IndexFunc(password, func(r rune) bool { return !strings.ContainsRune(tc.allowed, r) }); i >= 0 {
+					t.Fatalf("password %q has not allowed %q", password, password[i])
+				}
+
+				for _, class := range tc.classes {
+					if !strings.ContainsAny(password, class) {
+						t.Fatalf("password %q has no character of %q", password, class)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_Passphrase(t *testing.T) {
+	t.Parallel()
+
+	list := make(map[string]bool)
+	for _, word := range wordList() {
+		list[word] = true
+	}
+
+	if diff := cmp.Diff(7776, len(list)); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	passphrase, err := Generate(Policy{Words: 6, Separator: " "})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	picked := strings.Split(passphrase, " ")
+	if diff := cmp.Diff(6, len(picked)); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	for _, word := range picked {
+		if !list[word] {
+			t.Errorf("word %q is not in the wordlist", word)
+		}
+	}
+
+	if diff := cmp.Diff(77, int(Entropy(Policy{Words: 6}))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestPolicy_WithLength(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		policy   Policy
+		expected Policy
+	}{
+		{
+			name:     "test_with_length_characters",
+			policy:   Policy{Length: 6, Digits: true},
+			expected: Policy{Length: 12, Digits: true},
+		},
+		{
+			name:     "test_with_length_passphrase",
+			policy:   Policy{Words: 6, Separator: " "},
+			expected: Policy{Length: 12, Lower: true, Upper: true, Digits: true, Symbols: true, Require: true, Separator: " "},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := tc.policy.WithLength(12)
+			if diff := cmp.Diff(tc.expected, policy); diff != "" {
+				t.Fatalf("diff (+got, -want): %s", diff)
+			}
+
+			password, err := Generate(policy)
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+
+			if diff := cmp.Diff(12, len(password)); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var ErrAttachmentExists = errors.New("attachment already exists")
+
+// Attachment is a file of an entry stored encrypted outside of the vault as a blob.
+// Hash is the SHA-256 of the content, Key is the key the blob is sealed with,
+// Blob is the name of the blob in the attachment directory.
+type Attachment struct {
+	Name string
+	Size int64
+	Hash []byte
+	Key  []byte
+	Blob string
+}
+
+// Attachment returns the attachment of the entry with the name.
+func (e Entry) Attachment(name string) (Attachment, bool) {
+	for _, attachment := range e.Attachments {
+		if attachment.Name == name {
+			return attachment, true
+		}
+	}
+
+	return Attachment{}, false
+}
+
+// Attach adds the attachment to the entry with id. An entry can not have two attachments with the same name.
+func (s *Store) Attach(id string, attachment Attachment) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, ok := s.findByID(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	if _, ok = entry.Attachment(attachment.Name); ok {
+		return fmt.Errorf("%s: %w", attachment.Name, ErrAttachmentExists)
+	}
+
+	attachments := append(append([]Attachment(nil), entry.Attachments...), attachment)
+
+	return s.changeSync(id, ChangeEntry{Attachments: &attachments})
+}
+
+// Detach removes the attachment with the name from the entry with id.
+// The blob is still referenced by the history of the entry until the log is compacted.
+func (s *Store) Detach(id, name string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, ok := s.findByID(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	attachments := make([]Attachment, 0, len(entry.Attachments))
+	for _, attachment := range entry.Attachments {
+		if attachment.Name != name {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	if len(attachments) == len(entry.Attachments) {
+		return fmt.Errorf("attachment %s: %w", name, ErrNotFound)
+	}
+
+	return s.changeSync(id, ChangeEntry{Attachments: &attachments})
+}
+
+// Blobs returns the names of the blobs referenced by any tx of the log, including the history and the trash.
+func (s *Store) Blobs() map[string]bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	blobs := make(map[string]bool)
+	s.txManager.Each(func(tx Tx) {
+		for _, attachment := range tx.Payload.Attachments {
+			blobs[attachment.Blob] = true
+		}
+	})
+
+	return blobs
+}
+
+// FindAttachment returns an attachment of any entry with the content hash, it is used to store
+// the same content once.
+func (s *Store) FindAttachment(hash []byte) (Attachment, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	for _, entry := range s.data {
+		for _, attachment := range entry.Attachments {
+			if bytes.Equal(attachment.Hash, hash) {
+				return attachment, true
+			}
+		}
+	}
+
+	return Attachment{}, false
+}
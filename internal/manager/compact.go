@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+)
+
+type CompactOption func(*CompactOptions)
+
+type CompactOptions struct {
+	keepTxs int
+	keepAge time.Duration
+}
+
+// WithKeepTxs keeps the last n txs after the snapshot.
+func WithKeepTxs(n int) CompactOption {
+	return func(options *CompactOptions) {
+		options.keepTxs = n
+	}
+}
+
+// WithKeepAge keeps txs not older than d after the snapshot.
+func WithKeepAge(d time.Duration) CompactOption {
+	return func(options *CompactOptions) {
+		options.keepAge = d
+	}
+}
+
+// WithCompaction compacts the log on AutoCompact when more than threshold txs follow the snapshot.
+func WithCompaction(threshold int, opts ...CompactOption) Option {
+	return func(options *Options) {
+		options.compactThreshold = threshold
+		options.compact = opts
+	}
+}
+
+// Compact replaces persisted txs with a snapshot of the entries as they were after those txs.
+// The snapshot is chained to the hash of the last replaced tx that is kept as the base of the log
+// and the txs kept by opts are chained again after the snapshot. Pending txs are never compacted.
+// Compact returns the number of txs the log was shortened by.
+func (t *TxManager) Compact(opts ...CompactOption) (int, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.compact(opts)
+}
+
+// AutoCompact compacts the log with the options of WithCompaction when it is over the threshold.
+func (t *TxManager) AutoCompact() (int, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.opts.compactThreshold <= 0 || len(t.txList)-t.snapshot <= t.opts.compactThreshold {
+		return 0, nil
+	}
+
+	return t.compact(t.opts.compact)
+}
+
+func (t *TxManager) compact(opts []CompactOption) (int, error) {
+	var options CompactOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	cut := t.committed
+	if options.keepTxs > 0 && len(t.txList)-options.keepTxs < cut {
+		cut = len(t.txList) - options.keepTxs
+	}
+
+	if options.keepAge > 0 {
+		since := time.Now().Add(-options.keepAge)
+		for idx := t.snapshot; idx < cut; idx++ {
+			if !t.txList[idx].Ts.Before(since) {
+				cut = idx
+				break
+			}
+		}
+	}
+
+	if cut <= t.snapshot {
+		return 0, nil
+	}
+
+	r := newReplayer()
+	for _, tx := range t.txList[:cut] {
+		r.apply(tx)
+	}
+	entries := r.list()
+
+	log := txLog{txs: make([]Tx, len(entries), len(entries)+len(t.txList)-cut), hash: t.hash, snapshot: len(entries)}
+	ts := time.Now().UTC()
+	for idx, entry := range entries {
+		log.txs[idx] = Tx{Kind: TxKindSnapshot, Ts: ts, Payload: entry}
+	}
+
+	log.base = t.prevHash(cut)
+	log.txs = append(log.txs, t.txList[cut:]...)
+	if err := log.chain(0); err != nil {
+		return 0, fmt.Errorf("chain snapshot: %w", err)
+	}
+	removed := len(t.txList) - len(log.txs)
+
+	t.txList, t.snapshot, t.base = log.txs, log.snapshot, log.base
+	t.committed = t.committed - cut + len(entries)
+	t.head = t.committedHead()
+
+	return removed, nil
+}
@@ -0,0 +1,266 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+func testCompactLog(t *testing.T, opts ...Option) *TxManager {
+	t.Helper()
+
+	manager := NewTxManager(opts...)
+	now := time.Now().UTC()
+	entries := make([]Entry, 4)
+	for idx := range entries {
+		entries[idx] = Entry{ID: uuid.New().String(), Title: fmt.Sprintf("title %d", idx), CreatedAt: now, UpdatedAt: now}
+		if err := manager.AddTx(entries[idx]); err != nil {
+			t.Fatalf("add tx: %v", err)
+		}
+	}
+
+	if err := manager.UpdateTx(Entry{ID: entries[0].ID, Password: "password", UpdatedAt: now}, FieldPassword); err != nil {
+		t.Fatalf("update tx: %v", err)
+	}
+
+	if err := manager.DelTx(entries[1]); err != nil {
+		t.Fatalf("del tx: %v", err)
+	}
+
+	if err := manager.UpdateTx(Entry{ID: entries[2].ID, Title: "title 2 1", UpdatedAt: now}, FieldTitle); err != nil {
+		t.Fatalf("update tx: %v", err)
+	}
+
+	manager.Commit()
+
+	return manager
+}
+
+func testReplay(manager *TxManager) []Entry {
+	r := newReplayer()
+	manager.Each(r.apply)
+
+	return r.list()
+}
+
+func TestTxManager_Compact(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		opts     []CompactOption
+		removed  int
+		snapshot int
+		kept     int
+	}{
+		{name: "test_compact_all", removed: 4, snapshot: 3, kept: 0},
+		{name: "test_compact_keep_txs", opts: []CompactOption{WithKeepTxs(2)}, removed: 1, snapshot: 4, kept: 2},
+		{name: "test_compact_keep_all", opts: []CompactOption{WithKeepTxs(10)}, removed: 0, snapshot: 0, kept: 7},
+		{name: "test_compact_keep_age", opts: []CompactOption{WithKeepAge(time.Hour)}, removed: 0, snapshot: 0, kept: 7},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := testCompactLog(t)
+			expected := testReplay(manager)
+			tail := make([]Tx, tc.kept)
+			copy(tail, manager.List()[len(manager.List())-tc.kept:])
+
+			removed, err := manager.Compact(tc.opts...)
+			if err != nil {
+				t.Fatalf("compact: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.removed, removed); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.snapshot, manager.snapshot); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			// the kept txs are chained again after the snapshot
+			if diff := cmp.Diff(tail, manager.List()[manager.snapshot:], cmpopts.IgnoreFields(Tx{}, "Hash")); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			if diff := cmp.Diff(expected, testReplay(manager)); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			if err = manager.Verify(); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+
+			restored := NewTxManager()
+			restored.Deserialize(manager.Serialize())
+			if err = restored.AddTx(Entry{ID: uuid.New().String(), Title: "title 4"}); err != nil {
+				t.Fatalf("add tx: %v", err)
+			}
+
+			if err = restored.Verify(); err != nil {
+				t.Fatalf("verify restored: %v", err)
+			}
+
+			restored.txList = append(restored.txList[:restored.snapshot], restored.txList[restored.snapshot+1:]...)
+			if tc.kept > 0 && restored.Verify() == nil {
+				t.Error("dropped tx after the snapshot is verified")
+			}
+		})
+	}
+}
+
+func TestTxManager_CompactTampered(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		tamper func(manager *TxManager)
+	}{
+		{
+			name: "test_compact_tampered_dropped_snapshot",
+			tamper: func(manager *TxManager) {
+				// the snapshot without its second tx chained again from nil
+				manager.txList = append(manager.txList[:1], manager.txList[2:]...)
+				manager.snapshot--
+				log := txLog{txs: manager.txList[:manager.snapshot], hash: manager.hash}
+				if err := log.chain(0); err != nil {
+					t.Fatalf("chain: %v", err)
+				}
+			},
+		},
+		{
+			name: "test_compact_tampered_replaced_snapshot",
+			tamper: func(manager *TxManager) {
+				manager.txList[0].Payload.Password = "replaced"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := testCompactLog(t)
+			if _, err := manager.Compact(WithKeepTxs(2)); err != nil {
+				t.Fatalf("compact: %v", err)
+			}
+
+			tc.tamper(manager)
+
+			restored := NewTxManager()
+			restored.Deserialize(manager.Serialize())
+
+			var chainErr *ChainError
+			if err := restored.Verify(); !errors.As(err, &chainErr) {
+				t.Errorf("verify: %v, want %v", err, ErrChainBroken)
+			}
+		})
+	}
+}
+
+func TestTxManager_CompactKeepAge(t *testing.T) {
+	t.Parallel()
+
+	manager := testCompactLog(t)
+	for idx := 0; idx < 5; idx++ {
+		manager.txList[idx].Ts = manager.txList[idx].Ts.Add(-48 * time.Hour)
+	}
+
+	if err := manager.chain(0); err != nil {
+		t.Fatalf("chain: %v", err)
+	}
+
+	removed, err := manager.Compact(WithKeepAge(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	// the first 5 txs are replaced by the snapshot of 4 entries, 2 txs are kept
+	if diff := cmp.Diff(1, removed); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = manager.Verify(); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+}
+
+func TestTxManager_CompactPending(t *testing.T) {
+	t.Parallel()
+
+	manager := testCompactLog(t)
+	if err := manager.AddTx(Entry{ID: uuid.New().String(), Title: "pending"}); err != nil {
+		t.Fatalf("add tx: %v", err)
+	}
+
+	if _, err := manager.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	pending := manager.Pending()
+	if diff := cmp.Diff(1, len(pending)); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff("pending", pending[0].Payload.Title); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err := manager.Verify(); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+}
+
+func TestStore_AutoCompact(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager(WithCompaction(4, WithKeepTxs(1))))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for idx := 0; idx < 3; idx++ {
+		if err = store.Add(Entry{ID: uuid.New().String(), Title: fmt.Sprintf("title %d", idx), CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	entries := store.List()
+	for idx := range entries {
+		if err = store.DeleteByID(entries[idx].ID); err != nil {
+			t.Fatalf("store delete: %v", err)
+		}
+	}
+
+	// the 5th tx is over the threshold, the first 4 committed txs are compacted into 2 entries
+	if diff := cmp.Diff([]uint8{TxKindSnapshot, TxKindSnapshot, TxKindDel, TxKindDel}, testKinds(store.txManager)); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = store.Verify(); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+}
+
+func testKinds(manager *TxManager) []uint8 {
+	kinds := make([]uint8, 0)
+	manager.Each(func(tx Tx) {
+		kinds = append(kinds, tx.Kind)
+	})
+
+	return kinds
+}
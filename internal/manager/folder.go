@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// CleanFolder returns the folder path without empty parts and leading and trailing /, "work//dev/" is "work/dev".
+func CleanFolder(folder string) string {
+	return strings.Trim(path.Clean("/"+folder), "/")
+}
+
+// CleanTags returns the tags trimmed without empty and duplicate tags in the given order.
+func CleanTags(tags []string) []string {
+	cleaned := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if _, ok := seen[tag]; ok || tag == "" {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		cleaned = append(cleaned, tag)
+	}
+
+	return cleaned
+}
+
+// ListByFolder returns entries whose path is in the folder or its subfolders in the order of List.
+// Path-like titles such as work/github are in the folder work too.
+func (s *Store) ListByFolder(folder string) []Entry {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	folder = CleanFolder(folder)
+	entries := make([]Entry, 0)
+	for _, entry := range s.data {
+		if folder == "" || strings.HasPrefix(entry.Path(), folder+"/") {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// ListByTag returns entries with the tag in the order of List.
+func (s *Store) ListByTag(tag string) []Entry {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.entries(s.index.byTag[tag])
+}
+
+// Tags returns all tags of entries sorted.
+func (s *Store) Tags() []string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	tags := make([]string, 0, len(s.index.byTag))
+	for tag := range s.index.byTag {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// Tag adds the tags to the entry with id, tags the entry already has are skipped.
+// Nothing is written when the entry has all the tags.
+func (s *Store) Tag(id string, tags ...string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, ok := s.findByID(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	tagged := CleanTags(append(append([]string(nil), entry.Tags...), tags...))
+	if len(tagged) == len(entry.Tags) {
+		return nil
+	}
+
+	return s.changeSync(id, ChangeEntry{Tags: &tagged})
+}
+
+// Untag removes the tags from the entry with id, tags the entry does not have are skipped.
+// Nothing is written when the entry has none of the tags.
+func (s *Store) Untag(id string, tags ...string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, ok := s.findByID(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	removed := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		removed[strings.TrimSpace(tag)] = struct{}{}
+	}
+
+	untagged := make([]string, 0, len(entry.Tags))
+	for _, tag := range entry.Tags {
+		if _, ok := removed[tag]; !ok {
+			untagged = append(untagged, tag)
+		}
+	}
+
+	if len(untagged) == len(entry.Tags) {
+		return nil
+	}
+
+	return s.changeSync(id, ChangeEntry{Tags: &untagged})
+}
@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestCleanFolder(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		folder   string
+		expected string
+	}{
+		{name: "test_clean_folder_empty", folder: "", expected: ""},
+		{name: "test_clean_folder_root", folder: "/", expected: ""},
+		{name: "test_clean_folder_trailing", folder: "work/", expected: "work"},
+		{name: "test_clean_folder_parts", folder: "/work//dev/", expected: "work/dev"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, CleanFolder(tc.folder)); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestStore_ListByFolder(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	entries := []Entry{
+		{ID: uuid.New().String(), Title: "github", Folder: "work", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "mail", Folder: "home", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "gitlab", Folder: "work/dev", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "work/jira", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "bank", Folder: "workshop", CreatedAt: now, UpdatedAt: now},
+	}
+
+	for _, entry := range entries {
+		if err = store.Add(entry); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		folder   string
+		expected []Entry
+	}{
+		{name: "test_list_by_folder_all", folder: "", expected: entries},
+		{name: "test_list_by_folder_subfolders", folder: "work/", expected: []Entry{entries[0], entries[2], entries[3]}},
+		{name: "test_list_by_folder_subfolder", folder: "work/dev", expected: []Entry{entries[2]}},
+		{name: "test_list_by_folder_not_found", folder: "games", expected: []Entry{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, store.ListByFolder(tc.folder)); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestStore_Tag(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	txManager := NewTxManager()
+	store, err := NewStore(deps.fs, txManager)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	entries := []Entry{
+		{ID: uuid.New().String(), Title: "github", Tags: []string{"dev"}, CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "mail", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "gitlab", CreatedAt: now, UpdatedAt: now},
+	}
+
+	for _, entry := range entries {
+		if err = store.Add(entry); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	if err = store.Tag(entries[2].ID, "dev", " 2fa ", "dev"); err != nil {
+		t.Fatalf("store tag: %v", err)
+	}
+
+	if err = store.Tag(entries[1].ID, "2fa"); err != nil {
+		t.Fatalf("store tag: %v", err)
+	}
+
+	// tags the entry already has and missing tags do not emit txs
+	if err = store.Tag(entries[1].ID, "2fa"); err != nil {
+		t.Fatalf("store tag: %v", err)
+	}
+
+	if err = store.Untag(entries[0].ID, "home"); err != nil {
+		t.Fatalf("store untag: %v", err)
+	}
+
+	if diff := cmp.Diff(len(entries)+2, len(txManager.txList)); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = store.Untag(entries[0].ID, "dev"); err != nil {
+		t.Fatalf("store untag: %v", err)
+	}
+
+	last := txManager.txList[len(txManager.txList)-1]
+	if diff := cmp.Diff(FieldTags, last.Fields); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	ids := func(entries []Entry) []string {
+		got := make([]string, len(entries))
+		for idx, entry := range entries {
+			got[idx] = entry.ID
+		}
+
+		return got
+	}
+
+	if diff := cmp.Diff([]string{entries[2].ID}, ids(store.ListByTag("dev"))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{entries[1].ID, entries[2].ID}, ids(store.ListByTag("2fa"))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"2fa", "dev"}, store.Tags()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	tagged, _ := store.FindByID(entries[2].ID)
+	if diff := cmp.Diff([]string{"dev", "2fa"}, tagged.Tags); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	incremental := store.List()
+	store.rebuild()
+	if diff := cmp.Diff(incremental, store.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
@@ -0,0 +1,167 @@
+package manager
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Hash algorithms of tx hashes, the algorithm is recorded in the serialized log.
+const (
+	HashSHA1    byte = 0x1
+	HashMD5     byte = 0x2
+	HashSHA256  byte = 0x3
+	HashBLAKE2b byte = 0x4
+)
+
+// minHashPrefix is the shortest hex prefix accepted by View, as in git.
+const minHashPrefix = 4
+
+const defaultHash = HashSHA256
+
+var (
+	ErrHashNotSupport      = errors.New("tx hash algorithm not supported")
+	ErrHashPrefixShort     = errors.New("tx hash prefix is too short")
+	ErrHashPrefixAmbiguous = errors.New("tx hash prefix is ambiguous")
+)
+
+type HashFunc func() hash.Hash
+
+// HashFuncFor returns HashFunc of the alg.
+func HashFuncFor(alg byte) (HashFunc, error) {
+	switch alg {
+	case HashSHA1:
+		return sha1.New, nil
+	case HashMD5:
+		return md5.New, nil
+	case HashSHA256:
+		return sha256.New, nil
+	case HashBLAKE2b:
+		return func() hash.Hash {
+			// blake2b.New256 fails on a key longer than 64 bytes only
+			h, _ := blake2b.New256(nil)
+			return h
+		}, nil
+	default:
+		return nil, fmt.Errorf("hash %#x: %w", alg, ErrHashNotSupport)
+	}
+}
+
+// Tags of the canonical tx encoding. New fields get new tags, tags are never reused.
+const (
+	tagPrev byte = iota + 1
+	tagKind
+	tagTs
+	tagFields
+	tagID
+	tagTitle
+	tagPassword
+	tagCreatedAt
+	tagUpdatedAt
+	tagUsername
+	tagURL
+	tagNotes
+	tagCustomField
+	tagFolder
+	tagTag
+	tagOTP
+	tagAttachment
+)
+
+// Tags of the canonical encoding of CustomField.
+const (
+	tagCustomFieldName byte = iota + 1
+	tagCustomFieldValue
+	tagCustomFieldType
+)
+
+// Tags of the canonical encoding of Attachment.
+const (
+	tagAttachmentName byte = iota + 1
+	tagAttachmentSize
+	tagAttachmentHash
+	tagAttachmentKey
+	tagAttachmentBlob
+)
+
+// encodeTx returns the canonical encoding of tx chained to prev that is hashed.
+// Every field is written as its tag, uvarint length and value in the order of tags.
+// Zero values are omitted, so fields added later do not change hashes of existing txs.
+func encodeTx(prev []byte, tx Tx) []byte {
+	buf := bytes.Buffer{}
+	writeBytes(&buf, tagPrev, prev)
+	writeUint(&buf, tagKind, uint64(tx.Kind))
+	writeUint(&buf, tagTs, uint64(tx.Ts.UnixNano()))
+	writeUint(&buf, tagFields, tx.Fields)
+	writeBytes(&buf, tagID, []byte(tx.Payload.ID))
+	writeBytes(&buf, tagTitle, []byte(tx.Payload.Title))
+	writeBytes(&buf, tagPassword, []byte(tx.Payload.Password))
+	writeUint(&buf, tagCreatedAt, uint64(tx.Payload.CreatedAt.UnixNano()))
+	writeUint(&buf, tagUpdatedAt, uint64(tx.Payload.UpdatedAt.UnixNano()))
+	writeBytes(&buf, tagUsername, []byte(tx.Payload.Username))
+	for _, url := range tx.Payload.URLs {
+		writeBytes(&buf, tagURL, []byte(url))
+	}
+	writeBytes(&buf, tagNotes, []byte(tx.Payload.Notes))
+	for _, field := range tx.Payload.Fields {
+		writeBytes(&buf, tagCustomField, encodeCustomField(field))
+	}
+	writeBytes(&buf, tagFolder, []byte(tx.Payload.Folder))
+	for _, tag := range tx.Payload.Tags {
+		writeBytes(&buf, tagTag, []byte(tag))
+	}
+	writeBytes(&buf, tagOTP, []byte(tx.Payload.OTP))
+	for _, attachment := range tx.Payload.Attachments {
+		writeBytes(&buf, tagAttachment, encodeAttachment(attachment))
+	}
+
+	return buf.Bytes()
+}
+
+func encodeAttachment(attachment Attachment) []byte {
+	buf := bytes.Buffer{}
+	writeBytes(&buf, tagAttachmentName, []byte(attachment.Name))
+	writeUint(&buf, tagAttachmentSize, uint64(attachment.Size))
+	writeBytes(&buf, tagAttachmentHash, attachment.Hash)
+	writeBytes(&buf, tagAttachmentKey, attachment.Key)
+	writeBytes(&buf, tagAttachmentBlob, []byte(attachment.Blob))
+
+	return buf.Bytes()
+}
+
+func encodeCustomField(field CustomField) []byte {
+	buf := bytes.Buffer{}
+	writeBytes(&buf, tagCustomFieldName, []byte(field.Name))
+	writeBytes(&buf, tagCustomFieldValue, []byte(field.Value))
+	writeUint(&buf, tagCustomFieldType, uint64(field.Type))
+
+	return buf.Bytes()
+}
+
+func writeBytes(buf *bytes.Buffer, tag byte, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	buf.WriteByte(tag)
+	buf.Write(lenBuf[:binary.PutUvarint(lenBuf, uint64(len(b)))])
+	buf.Write(b)
+}
+
+func writeUint(buf *bytes.Buffer, tag byte, v uint64) {
+	if v == 0 {
+		return
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	writeBytes(buf, tag, b)
+}
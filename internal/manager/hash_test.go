@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestGenerateHash_ID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	tx0 := Tx{Kind: TxKindAdd, Ts: now, Payload: Entry{ID: uuid.New().String(), Title: "title", CreatedAt: now, UpdatedAt: now}}
+	tx1 := tx0
+	tx1.Payload.ID = uuid.New().String()
+
+	for _, alg := range []byte{HashSHA1, HashMD5, HashSHA256, HashBLAKE2b} {
+		hash0, err := generateHash(alg, nil, tx0)
+		if err != nil {
+			t.Fatalf("generate hash %#x: %v", alg, err)
+		}
+
+		hash1, err := generateHash(alg, nil, tx1)
+		if err != nil {
+			t.Fatalf("generate hash %#x: %v", alg, err)
+		}
+
+		if cmp.Equal(hash0, hash1) {
+			t.Errorf("hash %#x does not depend on id", alg)
+		}
+	}
+
+	// the length prefix separates fields, moving bytes between title and password changes the hash
+	tx2 := tx0
+	tx2.Payload.Title, tx2.Payload.Password = "tit", "le"
+	hash0, _ := generateHash(HashSHA256, nil, tx0)
+	hash2, _ := generateHash(HashSHA256, nil, tx2)
+	if cmp.Equal(hash0, hash2) {
+		t.Error("hash does not separate fields")
+	}
+
+	if _, err := generateHash(0xff, nil, tx0); !errors.Is(err, ErrHashNotSupport) {
+		t.Errorf("generate hash: %v, want %v", err, ErrHashNotSupport)
+	}
+}
+
+func TestTxManager_RecordedHash(t *testing.T) {
+	t.Parallel()
+
+	manager := NewTxManager(WithHash(HashBLAKE2b))
+	if err := manager.AddTx(Entry{ID: uuid.New().String(), Title: "title"}); err != nil {
+		t.Fatalf("add tx: %v", err)
+	}
+
+	restored := NewTxManager()
+	restored.Deserialize(manager.Serialize())
+	if diff := cmp.Diff(HashBLAKE2b, restored.Hash()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err := restored.AddTx(Entry{ID: uuid.New().String(), Title: "title 1"}); err != nil {
+		t.Fatalf("add tx: %v", err)
+	}
+
+	if err := restored.Verify(); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+
+	if diff := cmp.Diff(NewTxManager().Hash(), HashSHA256); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestTxManager_View(t *testing.T) {
+	t.Parallel()
+
+	manager := NewTxManager()
+	manager.txList = []Tx{
+		{Hash: []byte{0xab, 0xcd, 0x01}},
+		{Hash: []byte{0xab, 0xcd, 0x02}},
+		{Hash: []byte{0x12, 0x34, 0x56}},
+	}
+
+	testCases := []struct {
+		name     string
+		prefix   string
+		expected []byte
+		err      error
+	}{
+		{name: "test_view_full", prefix: "abcd01", expected: []byte{0xab, 0xcd, 0x01}},
+		{name: "test_view_prefix", prefix: "1234", expected: []byte{0x12, 0x34, 0x56}},
+		{name: "test_view_upper", prefix: "ABCD02", expected: []byte{0xab, 0xcd, 0x02}},
+		{name: "test_view_ambiguous", prefix: "abcd", err: ErrHashPrefixAmbiguous},
+		{name: "test_view_short", prefix: "ab", err: ErrHashPrefixShort},
+		{name: "test_view_not_found", prefix: "ffff", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx, err := manager.View(tc.prefix)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("view: %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.expected, tx.Hash); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
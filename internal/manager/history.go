@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Revision is a tx of an entry with the state of the entry after it.
+// The state of a deleted entry is the last state before the delete.
+type Revision struct {
+	Tx
+	Entry Entry
+}
+
+// History returns revisions of the entry with id in the order of the tx log.
+// Entries that were deleted are returned as well, ErrNotFound is returned when the log has no tx of id.
+func (s *Store) History(id string) ([]Revision, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.history(id)
+}
+
+// Trash returns the last revisions of deleted entries, their Tx is the delete.
+func (s *Store) Trash() []Revision {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	last := make(map[string]Revision)
+	order := make([]string, 0)
+	s.txManager.Each(func(tx Tx) {
+		revision, ok := last[tx.Payload.ID]
+		if !ok {
+			order = append(order, tx.Payload.ID)
+		}
+
+		last[tx.Payload.ID] = Revision{Tx: tx, Entry: apply(revision.Entry, tx)}
+	})
+
+	trash := make([]Revision, 0)
+	for _, id := range order {
+		if last[id].Kind == TxKindDel {
+			trash = append(trash, last[id])
+		}
+	}
+
+	return trash
+}
+
+// RestoreFromTx brings the entry back to its state after the tx whose hash starts with hash.
+// The ID and CreatedAt of the entry are kept, a deleted entry is added again and
+// an existing one is updated, so the restore is a new tx and the history is kept.
+func (s *Store) RestoreFromTx(hash string) (Entry, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	tx, err := s.txManager.View(hash)
+	if err != nil {
+		return Entry{}, fmt.Errorf("view: %w", err)
+	}
+
+	revisions, err := s.history(tx.Payload.ID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("history: %w", err)
+	}
+
+	var restored Entry
+	for _, revision := range revisions {
+		if bytes.Equal(revision.Hash, tx.Hash) {
+			restored = revision.Entry
+		}
+	}
+
+	restored.UpdatedAt = time.Now().UTC()
+	applied := Tx{Kind: TxKindAdd, Payload: restored}
+	if _, ok := s.findByID(restored.ID); ok {
+		applied = Tx{Kind: TxKindUpdate, Payload: restored, Fields: allFields}
+		err = s.txManager.UpdateTx(restored, applied.Fields)
+	} else {
+		err = s.txManager.AddTx(restored)
+	}
+
+	if err != nil {
+		return Entry{}, fmt.Errorf("restore tx: %w", err)
+	}
+
+	s.apply(applied)
+
+	if err = s.sync(); err != nil {
+		return Entry{}, fmt.Errorf("sync: %w", err)
+	}
+
+	return restored, nil
+}
+
+func (s *Store) history(id string) ([]Revision, error) {
+	revisions := make([]Revision, 0)
+	var current Entry
+	s.txManager.Each(func(tx Tx) {
+		if tx.Payload.ID != id {
+			return
+		}
+
+		current = apply(current, tx)
+		revisions = append(revisions, Revision{Tx: tx, Entry: current})
+	})
+
+	if len(revisions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return revisions, nil
+}
+
+// apply returns the state of the entry after tx, a delete keeps the last state.
+func apply(entry Entry, tx Tx) Entry {
+	switch tx.Kind {
+	case TxKindAdd, TxKindSnapshot:
+		return tx.Payload
+	case TxKindUpdate:
+		applyUpdate(&entry, tx)
+	}
+
+	return entry
+}
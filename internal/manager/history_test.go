@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestStore_History(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entry := Entry{ID: uuid.New().String(), Title: "title", Password: "title", CreatedAt: now, UpdatedAt: now}
+	other := Entry{ID: uuid.New().String(), Title: "other", Password: "other", CreatedAt: now, UpdatedAt: now}
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	for _, e := range []Entry{entry, other} {
+		if err := store.Add(e); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	passwords := []string{"title 1", "title 2"}
+	for idx := range passwords {
+		if err := store.ChangeByID(entry.ID, ChangeEntry{Password: &passwords[idx]}); err != nil {
+			t.Fatalf("store change by id: %v", err)
+		}
+	}
+
+	if err := store.DeleteByID(entry.ID); err != nil {
+		t.Fatalf("store delete by id: %v", err)
+	}
+
+	revisions, err := store.History(entry.ID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	kinds := make([]uint8, len(revisions))
+	secrets := make([]string, len(revisions))
+	for idx, revision := range revisions {
+		kinds[idx] = revision.Kind
+		secrets[idx] = revision.Entry.Password
+
+		if diff := cmp.Diff(entry.Title, revision.Entry.Title); diff != "" {
+			t.Errorf("diff (+got, -want): %s", diff)
+		}
+	}
+
+	if diff := cmp.Diff([]uint8{TxKindAdd, TxKindUpdate, TxKindUpdate, TxKindDel}, kinds); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"title", "title 1", "title 2", "title 2"}, secrets); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if _, err = store.History(uuid.New().String()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("history of unknown id: %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestStore_RestoreFromTx(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Add(-time.Hour)
+	entry := Entry{ID: uuid.New().String(), Title: "title", Password: "title", CreatedAt: now, UpdatedAt: now}
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	if err = store.Add(entry); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	password := "title 1"
+	if err = store.ChangeByID(entry.ID, ChangeEntry{Password: &password}); err != nil {
+		t.Fatalf("store change by id: %v", err)
+	}
+
+	revisions, err := store.History(entry.ID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	restored, err := store.RestoreFromTx(revisions[0].Hex())
+	if err != nil {
+		t.Fatalf("restore from tx: %v", err)
+	}
+
+	got, _ := store.FindByID(entry.ID)
+	if diff := cmp.Diff(restored, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff(Entry{ID: entry.ID, Title: "title", Password: "title", CreatedAt: now, UpdatedAt: got.UpdatedAt}, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = store.DeleteByID(entry.ID); err != nil {
+		t.Fatalf("store delete by id: %v", err)
+	}
+
+	trash := store.Trash()
+	if diff := cmp.Diff(1, len(trash)); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff(got, trash[0].Entry); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if _, err = store.RestoreFromTx(trash[0].Hex()); err != nil {
+		t.Fatalf("restore from tx: %v", err)
+	}
+
+	if diff := cmp.Diff(0, len(store.Trash())); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	restored, ok := store.FindByID(entry.ID)
+	if diff := cmp.Diff(true, ok); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff(now, restored.CreatedAt); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if _, err = store.RestoreFromTx("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("restore unknown tx: %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,181 @@
+package manager
+
+// index maps entry IDs to positions in Store.data, and titles, paths and tags to IDs in the order of Store.data.
+type index struct {
+	byID    map[string]int
+	byTitle map[string][]string
+	byPath  map[string][]string
+	byTag   map[string][]string
+}
+
+// indexedFields are the update fields that change the keys of an entry in the index.
+const indexedFields = FieldTitle | FieldFolder | FieldTags
+
+func newIndex(data []Entry) index {
+	idx := index{
+		byID:    make(map[string]int, len(data)),
+		byTitle: make(map[string][]string),
+		byPath:  make(map[string][]string),
+		byTag:   make(map[string][]string),
+	}
+	for pos, entry := range data {
+		idx.add(entry, pos)
+	}
+
+	return idx
+}
+
+func (i index) add(entry Entry, pos int) {
+	i.byID[entry.ID] = pos
+	i.link(entry)
+}
+
+func (i index) remove(entry Entry) {
+	delete(i.byID, entry.ID)
+	i.unlink(entry)
+}
+
+// link adds the keys of the entry, the entry must be the last one of data with the keys or be sorted after.
+func (i index) link(entry Entry) {
+	insertKey(i.byTitle, entry.Title, entry.ID)
+	insertKey(i.byPath, entry.Path(), entry.ID)
+	for _, tag := range entry.Tags {
+		insertKey(i.byTag, tag, entry.ID)
+	}
+}
+
+func (i index) unlink(entry Entry) {
+	removeKey(i.byTitle, entry.Title, entry.ID)
+	removeKey(i.byPath, entry.Path(), entry.ID)
+	for _, tag := range entry.Tags {
+		removeKey(i.byTag, tag, entry.ID)
+	}
+}
+
+func insertKey(keys map[string][]string, key, id string) {
+	keys[key] = append(keys[key], id)
+}
+
+func removeKey(keys map[string][]string, key, id string) {
+	ids := keys[key]
+	for idx := range ids {
+		if ids[idx] == id {
+			ids = append(ids[:idx], ids[idx+1:]...)
+			break
+		}
+	}
+
+	if len(ids) == 0 {
+		delete(keys, key)
+		return
+	}
+
+	keys[key] = ids
+}
+
+// apply applies tx to data and the index and returns the changed data.
+// A delete shifts the following entries and updates their positions.
+func (i index) apply(data []Entry, tx Tx) []Entry {
+	pos, ok := i.byID[tx.Payload.ID]
+
+	switch tx.Kind {
+	case TxKindAdd, TxKindSnapshot:
+		if ok {
+			return data
+		}
+
+		i.add(tx.Payload, len(data))
+
+		return append(data, tx.Payload)
+	case TxKindUpdate:
+		if !ok {
+			return data
+		}
+
+		if tx.Fields&indexedFields != 0 {
+			i.unlink(data[pos])
+			applyUpdate(&data[pos], tx)
+			i.link(data[pos])
+			i.sortKeys(data[pos])
+
+			return data
+		}
+
+		applyUpdate(&data[pos], tx)
+	case TxKindDel:
+		if !ok {
+			return data
+		}
+
+		i.remove(data[pos])
+		data = append(data[:pos], data[pos+1:]...)
+		for idx := pos; idx < len(data); idx++ {
+			i.byID[data[idx].ID] = idx
+		}
+	}
+
+	return data
+}
+
+// sortKeys keeps IDs of the keys of the entry in the order of data after the entry was relinked.
+func (i index) sortKeys(entry Entry) {
+	i.sortKey(i.byTitle, entry.Title)
+	i.sortKey(i.byPath, entry.Path())
+	for _, tag := range entry.Tags {
+		i.sortKey(i.byTag, tag)
+	}
+}
+
+func (i index) sortKey(keys map[string][]string, key string) {
+	ids := keys[key]
+	for idx := len(ids) - 1; idx > 0 && i.byID[ids[idx]] < i.byID[ids[idx-1]]; idx-- {
+		ids[idx], ids[idx-1] = ids[idx-1], ids[idx]
+	}
+}
+
+// replayer replays a tx log in linear time. Deletes are applied to maps only and
+// the order of entries is resolved once by list.
+type replayer struct {
+	order   []string
+	pos     map[string]int
+	entries map[string]Entry
+}
+
+func newReplayer() *replayer {
+	return &replayer{pos: make(map[string]int), entries: make(map[string]Entry)}
+}
+
+func (r *replayer) apply(tx Tx) {
+	id := tx.Payload.ID
+
+	switch tx.Kind {
+	case TxKindAdd, TxKindSnapshot:
+		if _, ok := r.entries[id]; ok {
+			return
+		}
+
+		r.pos[id] = len(r.order)
+		r.order = append(r.order, id)
+		r.entries[id] = tx.Payload
+	case TxKindUpdate:
+		if entry, ok := r.entries[id]; ok {
+			applyUpdate(&entry, tx)
+			r.entries[id] = entry
+		}
+	case TxKindDel:
+		delete(r.entries, id)
+		delete(r.pos, id)
+	}
+}
+
+// list returns the entries in the order they were added.
+func (r *replayer) list() []Entry {
+	data := make([]Entry, 0, len(r.entries))
+	for idx, id := range r.order {
+		if pos, ok := r.pos[id]; ok && pos == idx {
+			data = append(data, r.entries[id])
+		}
+	}
+
+	return data
+}
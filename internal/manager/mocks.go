@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: Store.go
+// Source: store.go
 
 // Package manager is a generated GoMock package.
 package manager
@@ -10,31 +10,31 @@ import (
 	gomock "github.com/golang/mock/gomock"
 )
 
-// MockCryptFS is a mock of CryptFS interface.
-type MockCryptFS struct {
+// MockFS is a mock of FS interface.
+type MockFS struct {
 	ctrl     *gomock.Controller
-	recorder *MockCryptFSMockRecorder
+	recorder *MockFSMockRecorder
 }
 
-// MockCryptFSMockRecorder is the mock recorder for MockCryptFS.
-type MockCryptFSMockRecorder struct {
-	mock *MockCryptFS
+// MockFSMockRecorder is the mock recorder for MockFS.
+type MockFSMockRecorder struct {
+	mock *MockFS
 }
 
-// NewMockCryptFS creates a new mock instance.
-func NewMockCryptFS(ctrl *gomock.Controller) *MockCryptFS {
-	mock := &MockCryptFS{ctrl: ctrl}
-	mock.recorder = &MockCryptFSMockRecorder{mock}
+// NewMockFS creates a new mock instance.
+func NewMockFS(ctrl *gomock.Controller) *MockFS {
+	mock := &MockFS{ctrl: ctrl}
+	mock.recorder = &MockFSMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCryptFS) EXPECT() *MockCryptFSMockRecorder {
+func (m *MockFS) EXPECT() *MockFSMockRecorder {
 	return m.recorder
 }
 
 // Open mocks base method.
-func (m *MockCryptFS) Open() ([]byte, error) {
+func (m *MockFS) Open() ([]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Open")
 	ret0, _ := ret[0].([]byte)
@@ -43,13 +43,13 @@ func (m *MockCryptFS) Open() ([]byte, error) {
 }
 
 // Open indicates an expected call of Open.
-func (mr *MockCryptFSMockRecorder) Open() *gomock.Call {
+func (mr *MockFSMockRecorder) Open() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCryptFS)(nil).Open))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockFS)(nil).Open))
 }
 
 // Write mocks base method.
-func (m *MockCryptFS) Write(b []byte) error {
+func (m *MockFS) Write(b []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Write", b)
 	ret0, _ := ret[0].(error)
@@ -57,7 +57,111 @@ func (m *MockCryptFS) Write(b []byte) error {
 }
 
 // Write indicates an expected call of Write.
-func (mr *MockCryptFSMockRecorder) Write(b interface{}) *gomock.Call {
+func (mr *MockFSMockRecorder) Write(b interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockCryptFS)(nil).Write), b)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockFS)(nil).Write), b)
+}
+
+// MockCipherFS is a mock of CipherFS interface.
+type MockCipherFS struct {
+	ctrl     *gomock.Controller
+	recorder *MockCipherFSMockRecorder
+}
+
+// MockCipherFSMockRecorder is the mock recorder for MockCipherFS.
+type MockCipherFSMockRecorder struct {
+	mock *MockCipherFS
+}
+
+// NewMockCipherFS creates a new mock instance.
+func NewMockCipherFS(ctrl *gomock.Controller) *MockCipherFS {
+	mock := &MockCipherFS{ctrl: ctrl}
+	mock.recorder = &MockCipherFSMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCipherFS) EXPECT() *MockCipherFSMockRecorder {
+	return m.recorder
+}
+
+// Open mocks base method.
+func (m *MockCipherFS) Open() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockCipherFSMockRecorder) Open() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCipherFS)(nil).Open))
+}
+
+// VerifyCipher mocks base method.
+func (m *MockCipherFS) VerifyCipher() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCipher")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyCipher indicates an expected call of VerifyCipher.
+func (mr *MockCipherFSMockRecorder) VerifyCipher() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCipher", reflect.TypeOf((*MockCipherFS)(nil).VerifyCipher))
+}
+
+// Write mocks base method.
+func (m *MockCipherFS) Write(b []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockCipherFSMockRecorder) Write(b interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockCipherFS)(nil).Write), b)
+}
+
+// MockLocker is a mock of Locker interface.
+type MockLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockerMockRecorder
+}
+
+// MockLockerMockRecorder is the mock recorder for MockLocker.
+type MockLockerMockRecorder struct {
+	mock *MockLocker
+}
+
+// NewMockLocker creates a new mock instance.
+func NewMockLocker(ctrl *gomock.Controller) *MockLocker {
+	mock := &MockLocker{ctrl: ctrl}
+	mock.recorder = &MockLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocker) EXPECT() *MockLockerMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockLocker) Lock() (func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock")
+	ret0, _ := ret[0].(func() error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLockerMockRecorder) Lock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLocker)(nil).Lock))
 }
@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// minIDPrefix is the shortest ID prefix accepted by Resolve.
+const minIDPrefix = 4
+
+var ErrAmbiguous = errors.New("entry reference is ambiguous")
+
+// AmbiguousError is returned by Resolve when ref matches several entries.
+type AmbiguousError struct {
+	Ref        string
+	Candidates []Entry
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%s: %q matches %d entries", ErrAmbiguous, e.Ref, len(e.Candidates))
+}
+
+func (e *AmbiguousError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// Path returns the path-like name of the entry, the folder and the title separated by /.
+func (e Entry) Path() string {
+	if e.Folder == "" {
+		return e.Title
+	}
+
+	return e.Folder + "/" + e.Title
+}
+
+// Resolve returns the entry referenced by ref. The reference is matched in order against
+// the exact ID, the exact path, the exact title of an entry in any folder and a unique ID prefix
+// of at least 4 characters, the first kind of match that finds entries wins.
+func (s *Store) Resolve(ref string) (Entry, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if entry, ok := s.findByID(ref); ok {
+		return entry, nil
+	}
+
+	if entries := s.findByPath(ref); len(entries) > 0 {
+		return one(ref, entries)
+	}
+
+	if entries := s.findByTitle(ref); len(entries) > 0 {
+		return one(ref, entries)
+	}
+
+	if len(ref) >= minIDPrefix {
+		if entries := s.findByIDPrefix(ref); len(entries) > 0 {
+			return one(ref, entries)
+		}
+	}
+
+	return Entry{}, fmt.Errorf("entry %q: %w", ref, ErrNotFound)
+}
+
+// ResolveID returns the entry with the ID or a unique ID prefix of at least 4 characters.
+func (s *Store) ResolveID(id string) (Entry, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if entry, ok := s.findByID(id); ok {
+		return entry, nil
+	}
+
+	if len(id) >= minIDPrefix {
+		if entries := s.findByIDPrefix(id); len(entries) > 0 {
+			return one(id, entries)
+		}
+	}
+
+	return Entry{}, fmt.Errorf("entry with id %s: %w", id, ErrNotFound)
+}
+
+// findByPath returns entries with the path in the order of List.
+func (s *Store) findByPath(path string) []Entry {
+	return s.entries(s.index.byPath[path])
+}
+
+func (s *Store) findByIDPrefix(prefix string) []Entry {
+	prefix = strings.ToLower(prefix)
+	entries := make([]Entry, 0)
+	for _, entry := range s.data {
+		if strings.HasPrefix(entry.ID, prefix) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func one(ref string, entries []Entry) (Entry, error) {
+	if len(entries) > 1 {
+		return Entry{}, &AmbiguousError{Ref: ref, Candidates: entries}
+	}
+
+	return entries[0], nil
+}
@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore_Resolve(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	entries := []Entry{
+		{ID: "1f0e4b1c-0000-4000-8000-000000000001", Title: "work/github", CreatedAt: now, UpdatedAt: now},
+		{ID: "1f0e4b1c-0000-4000-8000-000000000002", Title: "home/mail", CreatedAt: now, UpdatedAt: now},
+		{ID: "2a7d9e00-0000-4000-8000-000000000003", Title: "home/mail", CreatedAt: now, UpdatedAt: now},
+		{ID: "3c5b0a00-0000-4000-8000-000000000004", Title: "1f0e", CreatedAt: now, UpdatedAt: now},
+		{ID: "4d6c1b00-0000-4000-8000-000000000005", Title: "gitlab", Folder: "work/dev", CreatedAt: now, UpdatedAt: now},
+		{ID: "5e7d2c00-0000-4000-8000-000000000006", Title: "vpn", Folder: "work", CreatedAt: now, UpdatedAt: now},
+		{ID: "6f8e3d00-0000-4000-8000-000000000007", Title: "vpn", Folder: "home", CreatedAt: now, UpdatedAt: now},
+	}
+
+	for _, entry := range entries {
+		if err = store.Add(entry); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		ref        string
+		expected   Entry
+		err        error
+		candidates []Entry
+	}{
+		{name: "test_resolve_id", ref: entries[1].ID, expected: entries[1]},
+		{name: "test_resolve_id_prefix", ref: "2a7d", expected: entries[2]},
+		{name: "test_resolve_id_prefix_upper", ref: "2A7D9E", expected: entries[2]},
+		{name: "test_resolve_path", ref: "work/github", expected: entries[0]},
+		{name: "test_resolve_folder_path", ref: "work/dev/gitlab", expected: entries[4]},
+		{name: "test_resolve_title_in_folder", ref: "gitlab", expected: entries[4]},
+		{name: "test_resolve_ambiguous_title_in_folders", ref: "vpn", err: ErrAmbiguous, candidates: entries[5:7]},
+		{name: "test_resolve_path_before_prefix", ref: "1f0e", expected: entries[3]},
+		{name: "test_resolve_ambiguous_path", ref: "home/mail", err: ErrAmbiguous, candidates: entries[1:3]},
+		{name: "test_resolve_ambiguous_prefix", ref: "1f0e4b", err: ErrAmbiguous, candidates: entries[0:2]},
+		{name: "test_resolve_short_prefix", ref: "2a7", err: ErrNotFound},
+		{name: "test_resolve_not_found", ref: "github", err: ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			entry, err := store.Resolve(tc.ref)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("resolve: %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.expected, entry); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			var ambiguous *AmbiguousError
+			if errors.As(err, &ambiguous) {
+				if diff := cmp.Diff(tc.candidates, ambiguous.Candidates); diff != "" {
+					t.Errorf("diff (+got, -want): %s", diff)
+				}
+			}
+		})
+	}
+
+	if _, err = store.ResolveID("1f0e"); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("resolve id: %v, want %v", err, ErrAmbiguous)
+	}
+}
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"sync"
@@ -21,21 +22,59 @@ type CipherFS interface {
 	VerifyCipher() error
 }
 
+// Locker is implemented by CipherFS that can lock the vault for a read-modify-write cycle.
+type Locker interface {
+	Lock() (unlock func() error, err error)
+}
+
+// Types of CustomField.
+const (
+	CustomFieldText   byte = 0x0
+	CustomFieldHidden byte = 0x1
+	CustomFieldURL    byte = 0x2
+	CustomFieldEmail  byte = 0x3
+)
+
 type Entry struct {
 	ID        string
 	Title     string
 	Password  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Username  string
+	URLs      []string
+	Notes     string
+	Fields    []CustomField
+	// Folder is the folder path of the entry such as work/dev, parts are separated by /
+	Folder string
+	Tags   []string
+	// OTP is the otpauth URI or the base32 seed of the TOTP of the entry
+	OTP         string
+	Attachments []Attachment
+}
+
+// CustomField is a named value of an entry, Type is one of CustomField* types.
+type CustomField struct {
+	Name  string
+	Value string
+	Type  byte
 }
 
 type ChangeEntry struct {
-	Title    *string
-	Password *string
+	Title       *string
+	Password    *string
+	Username    *string
+	URLs        *[]string
+	Notes       *string
+	Fields      *[]CustomField
+	Folder      *string
+	Tags        *[]string
+	OTP         *string
+	Attachments *[]Attachment
 }
 
 func NewStore(fs CipherFS, txManager *TxManager) (*Store, error) {
-	s := &Store{fs: fs, txManager: txManager, data: make([]Entry, 0)}
+	s := &Store{fs: fs, txManager: txManager, data: make([]Entry, 0), index: newIndex(nil)}
 	if err := s.load(); err != nil {
 		return nil, fmt.Errorf("load tx: %w", err)
 	}
@@ -48,18 +87,21 @@ type Store struct {
 
 	mtx       sync.RWMutex
 	data      []Entry
+	index     index
 	txManager *TxManager
+	// loaded is the checksum of the vault content as it was last loaded or written
+	loaded [sha256.Size]byte
 }
 
 func (s *Store) Add(e Entry) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
 	if err := s.txManager.AddTx(e); err != nil {
 		return fmt.Errorf("add tx: %w", err)
 	}
 
-	s.mtx.Lock()
-	defer s.mtx.Unlock()
-
-	s.rebuild()
+	s.apply(Tx{Kind: TxKindAdd, Payload: e})
 
 	if err := s.sync(); err != nil {
 		return fmt.Errorf("sync: %w", err)
@@ -81,7 +123,7 @@ func (s *Store) DeleteByID(id string) error {
 		return fmt.Errorf("add tx: %w", err)
 	}
 
-	s.rebuild()
+	s.apply(Tx{Kind: TxKindDel, Payload: entry})
 
 	if err := s.sync(); err != nil {
 		return fmt.Errorf("sync: %w", err)
@@ -103,7 +145,44 @@ func (s *Store) DeleteByNumber(pos int) error {
 		return fmt.Errorf("add tx: %w", err)
 	}
 
-	s.rebuild()
+	s.apply(Tx{Kind: TxKindDel, Payload: entry})
+
+	if err := s.sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByIDs deletes all entries with ids and writes the vault once.
+// Nothing is deleted if any of ids is not found.
+func (s *Store) DeleteByIDs(ids ...string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entries := make([]Entry, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		entry, ok := s.findByID(id)
+		if !ok {
+			return ErrNotFound
+		}
+
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range entries {
+		if err := s.txManager.DelTx(entry); err != nil {
+			return fmt.Errorf("del tx: %w", err)
+		}
+
+		s.apply(Tx{Kind: TxKindDel, Payload: entry})
+	}
 
 	if err := s.sync(); err != nil {
 		return fmt.Errorf("sync: %w", err)
@@ -119,6 +198,14 @@ func (s *Store) FindByID(id string) (Entry, bool) {
 	return s.findByID(id)
 }
 
+// FindByTitle returns entries with exactly the title in the order of List.
+func (s *Store) FindByTitle(title string) []Entry {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.findByTitle(title)
+}
+
 func (s *Store) List() []Entry {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
@@ -154,8 +241,6 @@ func (s *Store) ChangeByPos(pos int, changed ChangeEntry) error {
 		return fmt.Errorf("change: %w", err)
 	}
 
-	s.rebuild()
-
 	if err := s.sync(); err != nil {
 		return fmt.Errorf("sync: %w", err)
 	}
@@ -176,8 +261,6 @@ func (s *Store) ChangeByID(id string, changed ChangeEntry) error {
 		return fmt.Errorf("change: %w", err)
 	}
 
-	s.rebuild()
-
 	if err := s.sync(); err != nil {
 		return fmt.Errorf("sync: %w", err)
 	}
@@ -185,6 +268,14 @@ func (s *Store) ChangeByID(id string, changed ChangeEntry) error {
 	return nil
 }
 
+// Verify checks the hash chain of the tx log, see TxManager.Verify.
+func (s *Store) Verify() error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.txManager.Verify()
+}
+
 func (s *Store) load() error {
 	b, err := s.fs.Open()
 	if err != nil {
@@ -192,90 +283,254 @@ func (s *Store) load() error {
 	}
 
 	s.txManager.Deserialize(b)
+	s.loaded = sha256.Sum256(b)
 	s.rebuild()
 
 	return nil
 }
 
+// rebuild replays the whole tx log into data and rebuilds the index.
 func (s *Store) rebuild() {
-	s.data = s.data[:0]
-	s.txManager.Each(func(t1 Tx) {
-		if t1.Kind == TxKindAdd {
-			s.data = append(s.data, t1.Payload)
+	r := newReplayer()
+	s.txManager.Each(r.apply)
+	s.data = r.list()
+	s.index = newIndex(s.data)
+}
 
-			return
-		}
+// apply applies a tx added to the log to data and the index without replaying the log.
+func (s *Store) apply(tx Tx) {
+	s.data = s.index.apply(s.data, tx)
+}
 
-		if t1.Kind == TxKindDel {
-			for idx, entry := range s.data {
-				if entry.ID == t1.Payload.ID {
-					s.data = append(s.data[:idx], s.data[idx+1:]...)
-				}
-			}
+// applyUpdate sets the fields of TxKindUpdate tx on the entry.
+func applyUpdate(entry *Entry, tx Tx) {
+	if tx.Fields&FieldTitle != 0 {
+		entry.Title = tx.Payload.Title
+	}
 
-			return
-		}
-	})
-}
+	if tx.Fields&FieldPassword != 0 {
+		entry.Password = tx.Payload.Password
+	}
 
-func (s *Store) change(id string, changed ChangeEntry) error {
-	var entry *Entry
+	if tx.Fields&FieldUsername != 0 {
+		entry.Username = tx.Payload.Username
+	}
 
-	for _, e := range s.data {
-		if e.ID == id {
-			entry = &e
-			break
-		}
+	if tx.Fields&FieldURLs != 0 {
+		entry.URLs = tx.Payload.URLs
+	}
+
+	if tx.Fields&FieldNotes != 0 {
+		entry.Notes = tx.Payload.Notes
+	}
+
+	if tx.Fields&FieldCustomFields != 0 {
+		entry.Fields = tx.Payload.Fields
 	}
 
-	if entry == nil {
+	if tx.Fields&FieldFolder != 0 {
+		entry.Folder = tx.Payload.Folder
+	}
+
+	if tx.Fields&FieldTags != 0 {
+		entry.Tags = tx.Payload.Tags
+	}
+
+	if tx.Fields&FieldOTP != 0 {
+		entry.OTP = tx.Payload.OTP
+	}
+
+	if tx.Fields&FieldAttachments != 0 {
+		entry.Attachments = tx.Payload.Attachments
+	}
+
+	entry.UpdatedAt = tx.Payload.UpdatedAt
+}
+
+func (s *Store) change(id string, changed ChangeEntry) error {
+	if _, ok := s.findByID(id); !ok {
 		return ErrNotFound
 	}
 
+	var fields uint64
+	entry := Entry{ID: id, UpdatedAt: time.Now().UTC()}
+
 	if changed.Title != nil {
 		entry.Title = *changed.Title
+		fields |= FieldTitle
 	}
 
 	if changed.Password != nil {
 		entry.Password = *changed.Password
+		fields |= FieldPassword
 	}
 
-	entry.UpdatedAt = time.Now().UTC()
+	if changed.Username != nil {
+		entry.Username = *changed.Username
+		fields |= FieldUsername
+	}
 
-	if err := s.txManager.DelTx(*entry); err != nil {
-		return fmt.Errorf("del tx: %w", err)
+	if changed.URLs != nil {
+		entry.URLs = *changed.URLs
+		fields |= FieldURLs
 	}
 
-	if err := s.txManager.AddTx(*entry); err != nil {
-		return fmt.Errorf("add tx: %w", err)
+	if changed.Notes != nil {
+		entry.Notes = *changed.Notes
+		fields |= FieldNotes
+	}
+
+	if changed.Fields != nil {
+		entry.Fields = *changed.Fields
+		fields |= FieldCustomFields
+	}
+
+	if changed.Folder != nil {
+		entry.Folder = *changed.Folder
+		fields |= FieldFolder
+	}
+
+	if changed.Tags != nil {
+		entry.Tags = *changed.Tags
+		fields |= FieldTags
+	}
+
+	if changed.OTP != nil {
+		entry.OTP = *changed.OTP
+		fields |= FieldOTP
+	}
+
+	if changed.Attachments != nil {
+		entry.Attachments = *changed.Attachments
+		fields |= FieldAttachments
+	}
+
+	if err := s.txManager.UpdateTx(entry, fields); err != nil {
+		return fmt.Errorf("update tx: %w", err)
+	}
+
+	s.apply(Tx{Kind: TxKindUpdate, Payload: entry, Fields: fields})
+
+	return nil
+}
+
+// changeSync changes the entry with id and writes the vault.
+func (s *Store) changeSync(id string, changed ChangeEntry) error {
+	if err := s.change(id, changed); err != nil {
+		return fmt.Errorf("change: %w", err)
+	}
+
+	if err := s.sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
 	}
 
 	return nil
 }
 
 func (s *Store) findByID(id string) (Entry, bool) {
-	for _, entry := range s.data {
-		if entry.ID == id {
-			return entry, true
-		}
+	pos, ok := s.index.byID[id]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return s.data[pos], true
+}
+
+func (s *Store) findByTitle(title string) []Entry {
+	return s.entries(s.index.byTitle[title])
+}
+
+// entries returns the entries with ids.
+func (s *Store) entries(ids []string) []Entry {
+	entries := make([]Entry, len(ids))
+	for idx, id := range ids {
+		entries[idx] = s.data[s.index.byID[id]]
 	}
 
-	return Entry{}, false
+	return entries
 }
 
 func (s *Store) findByNumber(pos int) (Entry, bool) {
-	if pos > len(s.data)-1 {
+	if pos < 0 || pos > len(s.data)-1 {
 		return Entry{}, false
 	}
 
 	return s.data[pos], true
 }
 
+// Compact compacts the tx log under the vault lock, see TxManager.Compact.
+func (s *Store) Compact(opts ...CompactOption) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var removed int
+	err := s.syncWith(func() (err error) {
+		removed, err = s.txManager.Compact(opts...)
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("sync: %w", err)
+	}
+
+	return removed, nil
+}
+
+// sync writes the tx log under the vault lock. When the vault was changed by another process
+// since it was loaded, the log is reloaded and the pending txs are re-applied on top of it.
+// The log is compacted when it is over the threshold of WithCompaction.
 func (s *Store) sync() error {
+	return s.syncWith(func() error {
+		if _, err := s.txManager.AutoCompact(); err != nil {
+			return fmt.Errorf("auto compact: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// syncWith is sync that calls before on the reloaded log before it is written.
+func (s *Store) syncWith(before func() error) (err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return fmt.Errorf("lock: %w", err)
+	}
+
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil && err == nil {
+			err = fmt.Errorf("unlock: %w", unlockErr)
+		}
+	}()
+
+	b, err := s.fs.Open()
+	if err != nil {
+		return fmt.Errorf("fs load: %w", err)
+	}
+
+	if sha256.Sum256(b) != s.loaded {
+		s.txManager.Rebase(b)
+		s.rebuild()
+	}
+
+	if err = before(); err != nil {
+		return err
+	}
+
 	bytes := s.txManager.Serialize()
-	if err := s.fs.Write(bytes); err != nil {
+	if err = s.fs.Write(bytes); err != nil {
 		return fmt.Errorf("fs write: %w", err)
 	}
 
+	s.txManager.Commit()
+	s.loaded = sha256.Sum256(bytes)
+
 	return nil
 }
+
+func (s *Store) lock() (func() error, error) {
+	if l, ok := s.fs.(Locker); ok {
+		return l.Lock()
+	}
+
+	return func() error { return nil }, nil
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -34,17 +36,13 @@ func TestStore_Add(t *testing.T) {
 			t.Parallel()
 
 			deps := testProvideMockDeps(t)
-			deps.fs.
-				EXPECT().
-				Open().
-				Return(nil, nil).
-				AnyTimes()
-			deps.fs.
-				EXPECT().
-				Write(gomock.Any()).
-				Return(nil).AnyTimes()
-
-			store := NewStore(deps.fs, NewTxManager())
+			deps.expectStorage()
+
+			store, err := NewStore(deps.fs, NewTxManager())
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+
 			if err := store.Add(tc.entry); err != nil {
 				t.Fatalf("store add: %v", err)
 			}
@@ -88,17 +86,13 @@ func TestStore_DeleteByID(t *testing.T) {
 			t.Parallel()
 
 			deps := testProvideMockDeps(t)
-			deps.fs.
-				EXPECT().
-				Open().
-				Return(nil, nil).
-				AnyTimes()
-			deps.fs.
-				EXPECT().
-				Write(gomock.Any()).
-				Return(nil).AnyTimes()
-
-			store := NewStore(deps.fs, NewTxManager())
+			deps.expectStorage()
+
+			store, err := NewStore(deps.fs, NewTxManager())
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+
 			if err := store.Add(tc.entry); err != nil {
 				t.Fatalf("store add: %v", err)
 			}
@@ -128,6 +122,68 @@ func TestStore_DeleteByID(t *testing.T) {
 	}
 }
 
+func TestStore_DeleteByIDs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entries := make([]Entry, 3)
+	for i := range entries {
+		entries[i] = Entry{ID: uuid.New().String(), Title: "title", Password: "title", CreatedAt: now, UpdatedAt: now}
+	}
+
+	testCases := []struct {
+		name     string
+		ids      []string
+		expected []Entry
+		err      error
+	}{
+		{
+			name:     "test_delete_ids_0",
+			ids:      []string{entries[0].ID, entries[2].ID, entries[0].ID},
+			expected: []Entry{entries[1]},
+		},
+		{
+			name:     "test_delete_ids_not_found",
+			ids:      []string{entries[0].ID, uuid.New().String()},
+			expected: entries,
+			err:      ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := testProvideMockDeps(t)
+			deps.expectStorage()
+
+			store, err := NewStore(deps.fs, NewTxManager())
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+
+			for _, entry := range entries {
+				if err = store.Add(entry); err != nil {
+					t.Fatalf("store add: %v", err)
+				}
+			}
+
+			if err = store.DeleteByIDs(tc.ids...); !errors.Is(err, tc.err) {
+				t.Fatalf("store delete: got %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.expected, store.List()); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			if diff := cmp.Diff(len(entries)*2-len(tc.expected), len(store.txManager.List())); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
 func TestStore_Change(t *testing.T) {
 	t.Parallel()
 
@@ -159,17 +215,13 @@ func TestStore_Change(t *testing.T) {
 			t.Parallel()
 
 			deps := testProvideMockDeps(t)
-			deps.fs.
-				EXPECT().
-				Open().
-				Return(nil, nil).
-				AnyTimes()
-			deps.fs.
-				EXPECT().
-				Write(gomock.Any()).
-				Return(nil).AnyTimes()
-
-			store := NewStore(deps.fs, NewTxManager())
+			deps.expectStorage()
+
+			store, err := NewStore(deps.fs, NewTxManager())
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+
 			if err := store.Add(tc.entry); err != nil {
 				t.Fatalf("store add: %v", err)
 			}
@@ -210,16 +262,387 @@ func TestStore_Change(t *testing.T) {
 	}
 }
 
+func TestStore_ChangeFields(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entry := Entry{
+		ID:        uuid.New().String(),
+		Title:     "title",
+		Password:  "title",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Username:  "user",
+		URLs:      []string{"https://example.com"},
+		Notes:     "notes",
+	}
+
+	var data []byte
+	deps := testProvideMockDeps(t)
+	deps.expectSharedStorage(&data)
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	if err = store.Add(entry); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	urls := []string{"https://example.com", "https://example.org"}
+	fields := []CustomField{
+		{Name: "pin", Value: "1234", Type: CustomFieldHidden},
+		{Name: "site", Value: "https://example.net", Type: CustomFieldURL},
+	}
+	if err = store.ChangeByID(entry.ID, ChangeEntry{URLs: &urls, Fields: &fields}); err != nil {
+		t.Fatalf("store change by id: %v", err)
+	}
+
+	changed, _ := store.FindByID(entry.ID)
+	expected := entry
+	expected.URLs = urls
+	expected.Fields = fields
+	expected.UpdatedAt = changed.UpdatedAt
+
+	if diff := cmp.Diff(expected, changed); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	reloaded, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	if diff := cmp.Diff([]Entry{expected}, reloaded.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestStore_ChangeKeepsPosition(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	entries := []Entry{
+		{ID: uuid.New().String(), Title: "first", Password: "first", CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New().String(), Title: "second", Password: "second", CreatedAt: now, UpdatedAt: now},
+	}
+
+	for _, entry := range entries {
+		if err := store.Add(entry); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	title := "first 1"
+	if err := store.ChangeByID(entries[0].ID, ChangeEntry{Title: &title}); err != nil {
+		t.Fatalf("store change by id: %v", err)
+	}
+
+	got := store.List()
+	if diff := cmp.Diff([]string{entries[0].ID, entries[1].ID}, []string{got[0].ID, got[1].ID}); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff("first 1", got[0].Title); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff("first", got[0].Password); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	txs := store.txManager.txList
+	if diff := cmp.Diff([]uint8{TxKindAdd, TxKindAdd, TxKindUpdate}, []uint8{txs[0].Kind, txs[1].Kind, txs[2].Kind}); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestStore_RebuildDelAddLog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entry := Entry{ID: uuid.New().String(), Title: "title", Password: "title", CreatedAt: now, UpdatedAt: now}
+	changed := entry
+	changed.Password = "title 1"
+
+	txManager := NewTxManager()
+	for _, fn := range []func() error{
+		func() error { return txManager.AddTx(entry) },
+		func() error { return txManager.DelTx(entry) },
+		func() error { return txManager.AddTx(changed) },
+	} {
+		if err := fn(); err != nil {
+			t.Fatalf("tx: %v", err)
+		}
+	}
+
+	store := &Store{txManager: txManager}
+	store.rebuild()
+
+	if diff := cmp.Diff([]Entry{changed}, store.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestStore_SyncChangedVault(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entry0 := Entry{ID: uuid.New().String(), Title: "title 0", Password: "title 0", CreatedAt: now, UpdatedAt: now}
+	entry1 := Entry{ID: uuid.New().String(), Title: "title 1", Password: "title 1", CreatedAt: now, UpdatedAt: now}
+
+	var data []byte
+	deps0 := testProvideMockDeps(t)
+	deps0.expectSharedStorage(&data)
+	deps1 := testProvideMockDeps(t)
+	deps1.expectSharedStorage(&data)
+
+	store0, err := NewStore(deps0.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	store1, err := NewStore(deps1.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	if err = store0.Add(entry0); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	// store1 was loaded before entry0 was written and must not overwrite it
+	if err = store1.Add(entry1); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	if diff := cmp.Diff([]Entry{entry0, entry1}, store1.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	store, err := NewStore(deps0.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	if diff := cmp.Diff([]Entry{entry0, entry1}, store.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = store.txManager.Verify(); err != nil {
+		t.Errorf("verify rebased log: %v", err)
+	}
+}
+
+func TestStore_Index(t *testing.T) {
+	t.Parallel()
+
+	deps := testProvideMockDeps(t)
+	deps.expectStorage()
+
+	store, err := NewStore(deps.fs, NewTxManager())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	ids := make([]string, 6)
+	for idx := range ids {
+		ids[idx] = uuid.New().String()
+		entry := Entry{ID: ids[idx], Title: fmt.Sprintf("title %d", idx%2), CreatedAt: now, UpdatedAt: now}
+		if err = store.Add(entry); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	title := "title 1"
+	if err = store.ChangeByID(ids[0], ChangeEntry{Title: &title}); err != nil {
+		t.Fatalf("store change by id: %v", err)
+	}
+
+	if err = store.DeleteByIDs(ids[1], ids[4]); err != nil {
+		t.Fatalf("store delete by ids: %v", err)
+	}
+
+	titleIDs := func(entries []Entry) []string {
+		got := make([]string, len(entries))
+		for idx, entry := range entries {
+			got[idx] = entry.ID
+		}
+
+		return got
+	}
+
+	if diff := cmp.Diff([]string{ids[0], ids[3], ids[5]}, titleIDs(store.FindByTitle("title 1"))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{ids[2]}, titleIDs(store.FindByTitle("title 0"))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	for pos, entry := range store.List() {
+		found, ok := store.FindByID(entry.ID)
+		if !ok {
+			t.Fatalf("entry %s not found", entry.ID)
+		}
+
+		if diff := cmp.Diff(entry, found); diff != "" {
+			t.Errorf("diff (+got, -want): %s", diff)
+		}
+
+		if diff := cmp.Diff(pos, store.index.byID[entry.ID]); diff != "" {
+			t.Errorf("diff (+got, -want): %s", diff)
+		}
+	}
+
+	incremental := store.List()
+	store.rebuild()
+	if diff := cmp.Diff(incremental, store.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+// benchmarkTxManager returns a log of n added entries with every third entry edited and every fifth deleted.
+func benchmarkTxManager(b *testing.B, n int) (*TxManager, []string) {
+	b.Helper()
+
+	txManager := NewTxManager()
+	now := time.Now().UTC()
+	ids := make([]string, n)
+	for idx := range ids {
+		ids[idx] = uuid.New().String()
+		if err := txManager.AddTx(Entry{ID: ids[idx], Title: fmt.Sprintf("title %d", idx), CreatedAt: now, UpdatedAt: now}); err != nil {
+			b.Fatalf("add tx: %v", err)
+		}
+	}
+
+	for idx, id := range ids {
+		var err error
+		switch {
+		case idx%5 == 0:
+			err = txManager.DelTx(Entry{ID: id})
+		case idx%3 == 0:
+			err = txManager.UpdateTx(Entry{ID: id, Password: "password", UpdatedAt: now}, FieldPassword)
+		}
+
+		if err != nil {
+			b.Fatalf("tx: %v", err)
+		}
+	}
+
+	return txManager, ids
+}
+
+func BenchmarkStore_Rebuild(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		n := n
+		b.Run(fmt.Sprintf("entries_%d", n), func(b *testing.B) {
+			txManager, _ := benchmarkTxManager(b, n)
+			store := &Store{txManager: txManager}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				store.rebuild()
+			}
+		})
+	}
+}
+
+func BenchmarkStore_FindByID(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		n := n
+		b.Run(fmt.Sprintf("entries_%d", n), func(b *testing.B) {
+			txManager, ids := benchmarkTxManager(b, n)
+			store := &Store{txManager: txManager}
+			store.rebuild()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				store.FindByID(ids[i%n])
+			}
+		})
+	}
+}
+
+func BenchmarkStore_FindByTitle(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		n := n
+		b.Run(fmt.Sprintf("entries_%d", n), func(b *testing.B) {
+			txManager, _ := benchmarkTxManager(b, n)
+			store := &Store{txManager: txManager}
+			store.rebuild()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				store.FindByTitle(fmt.Sprintf("title %d", i%n))
+			}
+		})
+	}
+}
+
+func BenchmarkStore_ApplyUpdate(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		n := n
+		b.Run(fmt.Sprintf("entries_%d", n), func(b *testing.B) {
+			txManager, _ := benchmarkTxManager(b, n)
+			store := &Store{txManager: txManager}
+			store.rebuild()
+			entries := store.List()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				entry := entries[i%len(entries)]
+				store.apply(Tx{Kind: TxKindUpdate, Payload: Entry{ID: entry.ID, Password: "password 1"}, Fields: FieldPassword})
+			}
+		})
+	}
+}
+
 type mockDeps struct {
 	ctrl *gomock.Controller
-	fs   *MockCryptFS
+	fs   *MockCipherFS
+}
+
+// expectStorage makes fs keep the written data and return it on open, as a file does.
+func (d mockDeps) expectStorage() {
+	d.expectSharedStorage(new([]byte))
+}
+
+// expectSharedStorage makes fs keep the written data in data, fs of several stores can share it.
+func (d mockDeps) expectSharedStorage(data *[]byte) {
+	d.fs.
+		EXPECT().
+		Open().
+		DoAndReturn(func() ([]byte, error) {
+			return *data, nil
+		}).
+		AnyTimes()
+	d.fs.
+		EXPECT().
+		Write(gomock.Any()).
+		DoAndReturn(func(b []byte) error {
+			*data = b
+
+			return nil
+		}).
+		AnyTimes()
 }
 
-func testProvideMockDeps(t *testing.T) mockDeps {
+func testProvideMockDeps(t testing.TB) mockDeps {
 	var deps mockDeps
 
 	deps.ctrl = gomock.NewController(t)
-	deps.fs = NewMockCryptFS(deps.ctrl)
+	deps.fs = NewMockCipherFS(deps.ctrl)
 
 	return deps
 }
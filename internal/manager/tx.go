@@ -2,12 +2,10 @@ package manager
 
 import (
 	"bytes"
-	"crypto/md5"
-	"crypto/sha1"
-	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"hash"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,58 +14,99 @@ import (
 )
 
 const (
-	TxKindAdd uint8 = 0x0
-	TxKindDel uint8 = 0x2
+	TxKindAdd    uint8 = 0x0
+	TxKindUpdate uint8 = 0x1
+	TxKindDel    uint8 = 0x2
+	// TxKindSnapshot adds an entry in the state it had when the log was compacted.
+	TxKindSnapshot uint8 = 0x3
 )
 
-type HashFunc func() hash.Hash
+// Versions of the serialized tx log.
+const (
+	// TxListVersionLegacy logs have hashes computed independently, they are chained on load.
+	TxListVersionLegacy byte = 0x0
+	// TxListVersionChained logs have every tx hash computed over the hash of the previous tx.
+	TxListVersionChained byte = 0x1
+	// TxListVersionCanonical logs have chained hashes over the canonical encoding of all tx fields
+	// computed with the hash algorithm recorded in the log. Logs of earlier versions are rehashed on load.
+	TxListVersionCanonical byte = 0x2
+	// TxListVersionSnapshotChained logs chain the snapshot to the hash of the last tx it replaced and the txs
+	// after the snapshot to its last tx. Snapshots of earlier logs are chained from nil and are rehashed on load.
+	TxListVersionSnapshotChained byte = 0x3
+)
 
-var defaultHasher = SHA1()
+var ErrChainBroken = errors.New("tx chain broken")
 
-// SHA1 returns a new hash.Hash computing the SHA1 checksum
-func SHA1() func() hash.Hash {
-	return func() hash.Hash {
-		return sha1.New()
-	}
+// ChainError is returned by Verify for the first tx whose hash does not match the chain.
+// When txs were dropped from the end of the log, Index is the length of the log and Hash
+// is the recorded hash of the last tx.
+type ChainError struct {
+	Index int
+	Hash  string
 }
 
-// MD5 returns a new hash.Hash computing the MD5 checksum
-func MD5() func() hash.Hash {
-	return func() hash.Hash {
-		return md5.New()
-	}
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("%s at tx %d (%s)", ErrChainBroken, e.Index, e.Hash)
 }
 
+func (e *ChainError) Is(target error) bool {
+	return target == ErrChainBroken
+}
+
+// Fields of Entry changed by TxKindUpdate.
+const (
+	FieldTitle uint64 = 1 << iota
+	FieldPassword
+	FieldUsername
+	FieldURLs
+	FieldNotes
+	FieldCustomFields
+	FieldFolder
+	FieldTags
+	FieldOTP
+	FieldAttachments
+
+	allFields = FieldTitle | FieldPassword | FieldUsername | FieldURLs | FieldNotes | FieldCustomFields |
+		FieldFolder | FieldTags | FieldOTP | FieldAttachments
+)
+
+// Tx is a record of the log. TxKindUpdate carries the entry ID, UpdatedAt and the fields set in Fields only.
 type Tx struct {
 	Hash    []byte    `json:"hash"`
 	Kind    uint8     `json:"kind"`
 	Ts      time.Time `json:"ts"`
 	Payload Entry     `json:"payload"`
+	Fields  uint64    `json:"fields,omitempty"`
 }
 
-func (t Tx) Sha1() string {
+// Hex returns the hex encoded tx hash.
+func (t Tx) Hex() string {
 	return hex.EncodeToString(t.Hash)
 }
 
 type Option func(*Options)
 
 type Options struct {
-	hashFunc HashFunc
+	hash             byte
+	compactThreshold int
+	compact          []CompactOption
 }
 
-// WithHashFunc set hash func for generate tx hash
-func WithHashFunc(f HashFunc) Option {
+// WithHash sets the hash algorithm of new logs, logs that are loaded keep their recorded algorithm.
+func WithHash(alg byte) Option {
 	return func(options *Options) {
-		options.hashFunc = f
+		options.hash = alg
 	}
 }
 
 func NewTxManager(opts ...Option) *TxManager {
-	tx := &TxManager{txList: make([]Tx, 0), opts: Options{hashFunc: defaultHasher}}
+	tx := &TxManager{txList: make([]Tx, 0), opts: Options{hash: defaultHash}}
 	for _, o := range opts {
 		o(&tx.opts)
 	}
 
+	tx.hash = tx.opts.hash
+
 	return tx
 }
 
@@ -76,13 +115,32 @@ type TxManager struct {
 
 	mtx    sync.RWMutex
 	txList []Tx
+	// committed is the number of txs from the head of txList that are persisted, the rest are pending
+	committed int
+	// hash is the hash algorithm of the log
+	hash byte
+	// snapshot is the number of TxKindSnapshot txs at the head of txList
+	snapshot int
+	// base is the hash the first tx is chained to, the hash of the last tx replaced by the snapshot
+	base []byte
+	// head is the hash of the last persisted tx recorded in the log, nil for logs written without it
+	head []byte
 }
 
-func (t *TxManager) View(hash string) (Tx, bool) {
+// View returns the tx whose hex encoded hash starts with prefix.
+func (t *TxManager) View(prefix string) (Tx, error) {
 	t.mtx.RLock()
 	defer t.mtx.RUnlock()
 
-	return t.view(hash)
+	return t.view(prefix)
+}
+
+// Hash returns the hash algorithm of the log.
+func (t *TxManager) Hash() byte {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.hash
 }
 
 func (t *TxManager) List() []Tx {
@@ -113,12 +171,112 @@ func (t *TxManager) DelTx(e Entry) error {
 	return t.delTx(e)
 }
 
+// UpdateTx adds a tx changing fields of the entry with e.ID to the values of e.
+func (t *TxManager) UpdateTx(e Entry, fields uint64) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.updateTx(e, fields)
+}
+
+// Pending returns txs added since the last Deserialize, Rebase or Commit.
+func (t *TxManager) Pending() []Tx {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	pending := make([]Tx, len(t.txList)-t.committed)
+	copy(pending, t.txList[t.committed:])
+
+	return pending
+}
+
+// Commit marks all txs as persisted.
+func (t *TxManager) Commit() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.committed = len(t.txList)
+	t.head = t.committedHead()
+}
+
+// Rebase replaces persisted txs with the deserialized b and re-applies pending txs on top of them.
+// The pending txs are chained again to the last persisted tx.
+func (t *TxManager) Rebase(b []byte) {
+	log := t.deserialize(b)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	pending := t.txList[t.committed:]
+	t.txList = append(log.txs, pending...)
+	t.committed = len(log.txs)
+	t.hash, t.snapshot, t.base, t.head = log.hash, log.snapshot, log.base, log.head
+	_ = t.chain(t.committed)
+}
+
+// Verify walks the hash chain and returns ChainError for the first broken link.
+// The last persisted tx must match the head recorded in the log, so txs dropped from the end are found too.
+func (t *TxManager) Verify() error {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	for idx, tx := range t.txList {
+		hash, err := generateHash(t.hash, t.prevHash(idx), tx)
+		if err != nil {
+			return fmt.Errorf("generate hash: %w", err)
+		}
+
+		if !bytes.Equal(hash, tx.Hash) {
+			return &ChainError{Index: idx, Hash: tx.Hex()}
+		}
+	}
+
+	if t.head != nil && !bytes.Equal(t.head, t.committedHead()) {
+		return &ChainError{Index: t.committed, Hash: hex.EncodeToString(t.head)}
+	}
+
+	return nil
+}
+
+// committedHead returns the hash of the last persisted tx, nil when there is none.
+func (t *TxManager) committedHead() []byte {
+	if t.committed == 0 {
+		return nil
+	}
+
+	return t.txList[t.committed-1].Hash
+}
+
 func (t *TxManager) Deserialize(b []byte) {
+	log := t.deserialize(b)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.txList = append(t.txList, log.txs...)
+	t.committed = len(t.txList)
+	t.hash, t.snapshot, t.base, t.head = log.hash, log.snapshot, log.base, log.head
+}
+
+// txLog is a deserialized tx log.
+type txLog struct {
+	txs      []Tx
+	hash     byte
+	snapshot int
+	base     []byte
+	head     []byte
+}
+
+// deserialize returns the tx log of b.
+// Txs of logs written before TxListVersionCanonical are rehashed with the algorithm of new logs,
+// compacted logs written before TxListVersionSnapshotChained are chained again through the snapshot.
+func (t *TxManager) deserialize(b []byte) txLog {
 	if len(b) == 0 {
-		return
+		return txLog{hash: t.opts.hash}
 	}
 
 	list := gen.GetRootAsTxList(b, 0)
+	version := list.Version()
 	length := list.ListLength()
 	txs := make([]Tx, length)
 
@@ -135,25 +293,118 @@ func (t *TxManager) Deserialize(b []byte) {
 			var o gen.Entry
 			tx.Payload(&o)
 
+			var urls []string
+			for j := 0; j < o.UrlsLength(); j++ {
+				urls = append(urls, string(o.Urls(j)))
+			}
+
+			var tags []string
+			for j := 0; j < o.TagsLength(); j++ {
+				tags = append(tags, string(o.Tags(j)))
+			}
+
+			var fields []CustomField
+			for j := 0; j < o.FieldsLength(); j++ {
+				var field gen.CustomField
+				if o.Fields(&field, j) {
+					fields = append(fields, CustomField{Name: string(field.Name()), Value: string(field.Value()), Type: field.Type()})
+				}
+			}
+
+			var attachments []Attachment
+			for j := 0; j < o.AttachmentsLength(); j++ {
+				var attachment gen.Attachment
+				if o.Attachments(&attachment, j) {
+					attachments = append(attachments, Attachment{
+						Name: string(attachment.Name()),
+						Size: attachment.Size(),
+						Hash: cloneBytes(attachment.HashBytes()),
+						Key:  cloneBytes(attachment.KeyBytes()),
+						Blob: string(attachment.Blob()),
+					})
+				}
+			}
+
 			txs[i] = Tx{
-				Hash: hashBytes,
-				Kind: tx.Kind(),
-				Ts:   time.Unix(0, tx.Ts()),
+				Hash:   hashBytes,
+				Kind:   tx.Kind(),
+				Ts:     time.Unix(0, tx.Ts()),
+				Fields: tx.Fields(),
 				Payload: Entry{
-					ID:        string(o.Id()),
-					Title:     string(o.Title()),
-					Password:  string(o.Password()),
-					CreatedAt: time.Unix(0, o.CreatedAt()),
-					UpdatedAt: time.Unix(0, o.UpdatedAt()),
+					ID:          string(o.Id()),
+					Title:       string(o.Title()),
+					Password:    string(o.Password()),
+					CreatedAt:   time.Unix(0, o.CreatedAt()),
+					UpdatedAt:   time.Unix(0, o.UpdatedAt()),
+					Username:    string(o.Username()),
+					URLs:        urls,
+					Notes:       string(o.Notes()),
+					Fields:      fields,
+					Folder:      string(o.Folder()),
+					Tags:        tags,
+					OTP:         string(o.Otp()),
+					Attachments: attachments,
 				},
 			}
 		}
 	}
 
-	t.mtx.Lock()
-	defer t.mtx.Unlock()
+	if version < TxListVersionCanonical {
+		log := txLog{txs: txs, hash: t.opts.hash}
+		_ = log.chain(0)
+
+		return log
+	}
+
+	log := txLog{
+		txs:      txs,
+		hash:     list.Hash(),
+		snapshot: int(list.Snapshot()),
+		base:     cloneBytes(list.BaseBytes()),
+		head:     cloneBytes(list.HeadBytes()),
+	}
+
+	if version < TxListVersionSnapshotChained && log.snapshot > 0 {
+		_ = log.chain(0)
+		log.head = nil
+	}
+
+	return log
+}
+
+// chain recomputes hashes of txs starting from the from index over the hash of the previous tx.
+func (t *TxManager) chain(from int) error {
+	log := txLog{txs: t.txList, hash: t.hash, snapshot: t.snapshot, base: t.base}
+
+	return log.chain(from)
+}
+
+func (l txLog) chain(from int) error {
+	for idx := from; idx < len(l.txs); idx++ {
+		hash, err := generateHash(l.hash, l.prevHash(idx), l.txs[idx])
+		if err != nil {
+			return fmt.Errorf("generate hash: %w", err)
+		}
 
-	t.txList = append(t.txList, txs...)
+		l.txs[idx].Hash = hash
+	}
+
+	return nil
+}
+
+// prevHash returns the hash the tx at idx is chained to. The first tx is chained to base,
+// nil unless the log was compacted, every other tx to the tx before it, so the snapshot
+// and the txs after it are one chain.
+func (l txLog) prevHash(idx int) []byte {
+	if idx == 0 {
+		return l.base
+	}
+
+	return l.txs[idx-1].Hash
+}
+
+func (t *TxManager) prevHash(idx int) []byte {
+	return txLog{txs: t.txList, base: t.base}.prevHash(idx)
 }
 
 func (t *TxManager) Serialize() []byte {
@@ -166,6 +417,14 @@ func (t *TxManager) Serialize() []byte {
 		idOffset := builder.CreateString(tx.Payload.ID)
 		titleOffset := builder.CreateString(tx.Payload.Title)
 		passwordOffset := builder.CreateString(tx.Payload.Password)
+		usernameOffset := builder.CreateString(tx.Payload.Username)
+		notesOffset := builder.CreateString(tx.Payload.Notes)
+		urlsOffset := serializeStrings(builder, tx.Payload.URLs, gen.EntryStartUrlsVector)
+		folderOffset := builder.CreateString(tx.Payload.Folder)
+		tagsOffset := serializeStrings(builder, tx.Payload.Tags, gen.EntryStartTagsVector)
+		otpOffset := builder.CreateString(tx.Payload.OTP)
+		attachmentsOffset := serializeAttachments(builder, tx.Payload.Attachments)
+		fieldsOffset := serializeFields(builder, tx.Payload.Fields)
 
 		gen.EntryStart(builder)
 		gen.EntryAddId(builder, idOffset)
@@ -173,6 +432,14 @@ func (t *TxManager) Serialize() []byte {
 		gen.EntryAddPassword(builder, passwordOffset)
 		gen.EntryAddCreatedAt(builder, tx.Payload.CreatedAt.UnixNano())
 		gen.EntryAddUpdatedAt(builder, tx.Payload.UpdatedAt.UnixNano())
+		gen.EntryAddUsername(builder, usernameOffset)
+		gen.EntryAddUrls(builder, urlsOffset)
+		gen.EntryAddNotes(builder, notesOffset)
+		gen.EntryAddFields(builder, fieldsOffset)
+		gen.EntryAddFolder(builder, folderOffset)
+		gen.EntryAddTags(builder, tagsOffset)
+		gen.EntryAddOtp(builder, otpOffset)
+		gen.EntryAddAttachments(builder, attachmentsOffset)
 
 		entry := gen.EntryEnd(builder)
 
@@ -187,6 +454,7 @@ func (t *TxManager) Serialize() []byte {
 		gen.TxAddTs(builder, tx.Ts.UnixNano())
 		gen.TxAddKind(builder, tx.Kind)
 		gen.TxAddPayload(builder, entry)
+		gen.TxAddFields(builder, tx.Fields)
 
 		flatTxs[idx] = gen.TxEnd(builder)
 	}
@@ -197,8 +465,31 @@ func (t *TxManager) Serialize() []byte {
 	}
 	endVec := builder.EndVector(len(flatTxs))
 
+	gen.TxListStartBaseVector(builder, len(t.base))
+	for i := len(t.base) - 1; i >= 0; i-- {
+		builder.PrependByte(t.base[i])
+	}
+	baseOffset := builder.EndVector(len(t.base))
+
+	var headOffset flatbuffers.UOffsetT
+	if len(t.txList) > 0 {
+		head := t.txList[len(t.txList)-1].Hash
+		gen.TxListStartHeadVector(builder, len(head))
+		for i := len(head) - 1; i >= 0; i-- {
+			builder.PrependByte(head[i])
+		}
+		headOffset = builder.EndVector(len(head))
+	}
+
 	gen.TxListStart(builder)
 	gen.TxListAddList(builder, endVec)
+	gen.TxListAddSnapshot(builder, uint32(t.snapshot))
+	gen.TxListAddBase(builder, baseOffset)
+	if len(t.txList) > 0 {
+		gen.TxListAddHead(builder, headOffset)
+	}
+	gen.TxListAddVersion(builder, TxListVersionSnapshotChained)
+	gen.TxListAddHash(builder, t.hash)
 	endList := gen.TxListEnd(builder)
 
 	builder.Finish(endList)
@@ -206,54 +497,106 @@ func (t *TxManager) Serialize() []byte {
 	return builder.FinishedBytes()
 }
 
-func (t *TxManager) makeTx(kind uint8, e Entry) (Tx, error) {
-	ts := time.Now().UTC()
+func serializeStrings(
+	builder *flatbuffers.Builder,
+	strs []string,
+	start func(*flatbuffers.Builder, int) flatbuffers.UOffsetT,
+) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(strs))
+	for idx, str := range strs {
+		offsets[idx] = builder.CreateString(str)
+	}
 
-	hashBytes, err := t.generateHash(kind, ts, e)
-	if err != nil {
-		return Tx{}, fmt.Errorf("generate hash: %w", err)
+	start(builder, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(offsets[i])
 	}
 
-	return Tx{
-		Hash:    hashBytes,
-		Kind:    kind,
-		Ts:      ts,
-		Payload: e,
-	}, nil
+	return builder.EndVector(len(offsets))
 }
 
-func (t *TxManager) generateHash(kind byte, ts time.Time, e Entry) ([]byte, error) {
-	b := make([]byte, 0)
-	buf := bytes.NewBuffer(b)
+func serializeFields(builder *flatbuffers.Builder, fields []CustomField) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(fields))
+	for idx, field := range fields {
+		nameOffset := builder.CreateString(field.Name)
+		valueOffset := builder.CreateString(field.Value)
+
+		gen.CustomFieldStart(builder)
+		gen.CustomFieldAddName(builder, nameOffset)
+		gen.CustomFieldAddValue(builder, valueOffset)
+		gen.CustomFieldAddType(builder, field.Type)
+		offsets[idx] = gen.CustomFieldEnd(builder)
+	}
 
-	buf.WriteByte(kind)
+	gen.EntryStartFieldsVector(builder, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(offsets[i])
+	}
 
-	tsBuf := make([]byte, 8)
-	binary.LittleEndian.PutUint64(tsBuf, uint64(ts.UnixNano()))
-	if _, err := buf.Write(tsBuf); err != nil {
-		return nil, fmt.Errorf("buf write: %w", err)
+	return builder.EndVector(len(offsets))
+}
+
+func serializeAttachments(builder *flatbuffers.Builder, attachments []Attachment) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(attachments))
+	for idx, attachment := range attachments {
+		nameOffset := builder.CreateString(attachment.Name)
+		hashOffset := builder.CreateByteVector(attachment.Hash)
+		keyOffset := builder.CreateByteVector(attachment.Key)
+		blobOffset := builder.CreateString(attachment.Blob)
+
+		gen.AttachmentStart(builder)
+		gen.AttachmentAddName(builder, nameOffset)
+		gen.AttachmentAddSize(builder, attachment.Size)
+		gen.AttachmentAddHash(builder, hashOffset)
+		gen.AttachmentAddKey(builder, keyOffset)
+		gen.AttachmentAddBlob(builder, blobOffset)
+		offsets[idx] = gen.AttachmentEnd(builder)
 	}
 
-	if _, err := buf.Write([]byte(e.Title)); err != nil {
-		return nil, fmt.Errorf("buf write: %w", err)
+	gen.EntryStartAttachmentsVector(builder, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(offsets[i])
 	}
 
-	if _, err := buf.Write([]byte(e.Password)); err != nil {
-		return nil, fmt.Errorf("buf write: %w", err)
+	return builder.EndVector(len(offsets))
+}
+
+// cloneBytes copies b out of the flatbuffer, empty b is nil.
+func cloneBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
 	}
 
-	binary.LittleEndian.PutUint64(tsBuf, uint64(e.CreatedAt.UnixNano()))
-	if _, err := buf.Write(tsBuf); err != nil {
-		return nil, fmt.Errorf("buf write: %w", err)
+	return append([]byte(nil), b...)
+}
+
+func (t *TxManager) makeTx(kind uint8, e Entry, fields uint64) (Tx, error) {
+	tx := Tx{
+		Kind:    kind,
+		Ts:      time.Now().UTC(),
+		Payload: e,
+		Fields:  fields,
+	}
+
+	hashBytes, err := generateHash(t.hash, t.prevHash(len(t.txList)), tx)
+	if err != nil {
+		return Tx{}, fmt.Errorf("generate hash: %w", err)
 	}
 
-	binary.LittleEndian.PutUint64(tsBuf, uint64(e.UpdatedAt.UnixNano()))
-	if _, err := buf.Write(tsBuf); err != nil {
-		return nil, fmt.Errorf("buf write: %w", err)
+	tx.Hash = hashBytes
+
+	return tx, nil
+}
+
+// generateHash computes the hash of tx chained to the prev tx hash, prev is nil for the first tx.
+func generateHash(alg byte, prev []byte, tx Tx) ([]byte, error) {
+	hashFunc, err := HashFuncFor(alg)
+	if err != nil {
+		return nil, err
 	}
 
-	hasher := t.opts.hashFunc()
-	hasher.Write(buf.Bytes())
+	hasher := hashFunc()
+	hasher.Write(encodeTx(prev, tx))
 
 	return hasher.Sum(nil), nil
 }
@@ -264,18 +607,36 @@ func (t *TxManager) each(f func(t Tx)) {
 	}
 }
 
-func (t *TxManager) view(hash string) (Tx, bool) {
+func (t *TxManager) view(prefix string) (Tx, error) {
+	if len(prefix) < minHashPrefix {
+		return Tx{}, fmt.Errorf("%q: %w", prefix, ErrHashPrefixShort)
+	}
+
+	prefix = strings.ToLower(prefix)
+	var (
+		found Tx
+		count int
+	)
+
 	for _, tx := range t.txList {
-		if tx.Sha1() == hash {
-			return tx, true
+		if strings.HasPrefix(tx.Hex(), prefix) {
+			found = tx
+			count++
 		}
 	}
 
-	return Tx{}, false
+	switch count {
+	case 0:
+		return Tx{}, fmt.Errorf("tx %s: %w", prefix, ErrNotFound)
+	case 1:
+		return found, nil
+	default:
+		return Tx{}, fmt.Errorf("%q matches %d txs: %w", prefix, count, ErrHashPrefixAmbiguous)
+	}
 }
 
 func (t *TxManager) addTx(e Entry) error {
-	tx, err := t.makeTx(TxKindAdd, e)
+	tx, err := t.makeTx(TxKindAdd, e, 0)
 	if err != nil {
 		return fmt.Errorf("make tx: %w", err)
 	}
@@ -286,7 +647,18 @@ func (t *TxManager) addTx(e Entry) error {
 }
 
 func (t *TxManager) delTx(e Entry) error {
-	tx, err := t.makeTx(TxKindDel, e)
+	tx, err := t.makeTx(TxKindDel, e, 0)
+	if err != nil {
+		return fmt.Errorf("make tx: %w", err)
+	}
+
+	t.txList = append(t.txList, tx)
+
+	return nil
+}
+
+func (t *TxManager) updateTx(e Entry, fields uint64) error {
+	tx, err := t.makeTx(TxKindUpdate, e, fields)
 	if err != nil {
 		return fmt.Errorf("make tx: %w", err)
 	}
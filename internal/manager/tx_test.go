@@ -2,11 +2,14 @@ package manager
 
 import (
 	"crypto/sha1"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/polylab/mypass-cli/pkg/proto/gen"
 )
 
 func TestTxManager_AddTx(t *testing.T) {
@@ -57,12 +60,12 @@ func TestTxManager_AddTx(t *testing.T) {
 				t.Fatal("error added tx")
 			}
 
-			hash, err := manager.generateHash(TxKindAdd, manager.txList[0].Ts, tc.entry)
+			tc.expected.Ts = manager.txList[0].Ts
+			hash, err := generateHash(HashSHA256, nil, tc.expected)
 			if err != nil {
 				t.Fatalf("generate hash: %v", err)
 			}
 
-			tc.expected.Ts = manager.txList[0].Ts
 			tc.expected.Hash = hash
 			if diff := cmp.Diff(tc.expected, manager.txList[0]); diff != "" {
 				t.Errorf("diff (+got, -want): %s", diff)
@@ -124,12 +127,12 @@ func TestTxManager_DelTx(t *testing.T) {
 				t.Fatal("error added tx")
 			}
 
-			hash, err := manager.generateHash(TxKindDel, manager.txList[1].Ts, tc.entry)
+			tc.expected.Ts = manager.txList[1].Ts
+			hash, err := generateHash(HashSHA256, manager.txList[0].Hash, tc.expected)
 			if err != nil {
 				t.Fatalf("generate hash: %v", err)
 			}
 
-			tc.expected.Ts = manager.txList[1].Ts
 			tc.expected.Hash = hash
 			if diff := cmp.Diff(tc.expected, manager.txList[1]); diff != "" {
 				t.Errorf("diff (+got, -want): %s", diff)
@@ -138,6 +141,77 @@ func TestTxManager_DelTx(t *testing.T) {
 	}
 }
 
+func TestTxManager_UpdateTx(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+	updatedAt := time.Now().UTC()
+
+	testCases := []struct {
+		name     string
+		entry    Entry
+		fields   uint64
+		expected Tx
+	}{
+		{
+			name: "test_update_tx_0",
+			entry: Entry{
+				ID:        id,
+				Title:     "title 1",
+				UpdatedAt: updatedAt,
+			},
+			fields: FieldTitle,
+			expected: Tx{
+				Kind: TxKindUpdate,
+				Payload: Entry{
+					ID:        id,
+					Title:     "title 1",
+					UpdatedAt: updatedAt,
+				},
+				Fields: FieldTitle,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := NewTxManager()
+			if err := manager.UpdateTx(tc.entry, tc.fields); err != nil {
+				t.Fatalf("update tx: %v", err)
+			}
+
+			if len(manager.txList) == 0 {
+				t.Fatal("error added tx")
+			}
+
+			tc.expected.Ts = manager.txList[0].Ts
+			hash, err := generateHash(HashSHA256, nil, tc.expected)
+			if err != nil {
+				t.Fatalf("generate hash: %v", err)
+			}
+
+			other := tc.expected
+			other.Fields |= FieldPassword
+			otherHash, err := generateHash(HashSHA256, nil, other)
+			if err != nil {
+				t.Fatalf("generate hash: %v", err)
+			}
+
+			if cmp.Equal(hash, otherHash) {
+				t.Error("hash does not depend on fields")
+			}
+
+			tc.expected.Hash = hash
+			if diff := cmp.Diff(tc.expected, manager.txList[0]); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
 func TestTxManager_Serialize(t *testing.T) {
 	t.Parallel()
 
@@ -148,7 +222,7 @@ func TestTxManager_Serialize(t *testing.T) {
 	}{
 		{
 			name:        "test_serialize_0",
-			expectedLen: 4,
+			expectedLen: 6,
 			txs: []Tx{
 				{
 					Hash: func() []byte {
@@ -201,6 +275,49 @@ func TestTxManager_Serialize(t *testing.T) {
 						UpdatedAt: time.Now().UTC(),
 					},
 				},
+				{
+					Hash: func() []byte {
+						h := sha1.New()
+						h.Write([]byte(`test4`))
+
+						return h.Sum(nil)
+					}(),
+					Kind: TxKindUpdate,
+					Ts:   time.Now().UTC(),
+					Payload: Entry{
+						ID:        uuid.New().String(),
+						Password:  "title4 title4",
+						CreatedAt: time.Now().UTC(),
+						UpdatedAt: time.Now().UTC(),
+					},
+					Fields: FieldPassword,
+				},
+				{
+					Hash: func() []byte {
+						h := sha1.New()
+						h.Write([]byte(`test5`))
+
+						return h.Sum(nil)
+					}(),
+					Kind: TxKindUpdate,
+					Ts:   time.Now().UTC(),
+					Payload: Entry{
+						ID:        uuid.New().String(),
+						CreatedAt: time.Now().UTC(),
+						UpdatedAt: time.Now().UTC(),
+						Username:  "user",
+						URLs:      []string{"https://example.com", "https://login.example.com"},
+						Notes:     "notes\nnotes",
+						Fields: []CustomField{
+							{Name: "pin", Value: "1234", Type: CustomFieldHidden},
+							{Name: "email", Value: "user@example.com", Type: CustomFieldEmail},
+						},
+						Folder: "work/dev",
+						Tags:   []string{"dev", "2fa"},
+						OTP:    "otpauth://totp/john?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+					},
+					Fields: FieldUsername | FieldURLs | FieldNotes | FieldCustomFields | FieldFolder | FieldTags | FieldOTP,
+				},
 				{
 					Hash: func() []byte {
 						h := sha1.New()
@@ -230,15 +347,164 @@ func TestTxManager_Serialize(t *testing.T) {
 			tx := NewTxManager()
 			tx.txList = append(tx.txList, tc.txs...)
 			bytes := tx.Serialize()
-			tx.Deserialize(bytes)
 
-			if diff := cmp.Diff(tc.expectedLen, len(tx.txList)); diff != "" {
+			restored := NewTxManager()
+			restored.Deserialize(bytes)
+
+			if diff := cmp.Diff(tc.expectedLen, len(restored.txList)); diff != "" {
 				t.Errorf("diff (+got, -want): %s", diff)
 			}
 
-			if diff := cmp.Diff(tc.txs, tx.txList); diff != "" {
+			if diff := cmp.Diff(tc.txs, restored.txList); diff != "" {
 				t.Errorf("diff (+got, -want): %s", diff)
 			}
 		})
 	}
 }
+
+func TestTxManager_Verify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		tamper func(txs []Tx) []Tx
+		index  int
+	}{
+		{
+			name:   "test_verify_intact",
+			tamper: func(txs []Tx) []Tx { return txs },
+			index:  -1,
+		},
+		{
+			name:   "test_verify_dropped",
+			tamper: func(txs []Tx) []Tx { return append(txs[:1], txs[2:]...) },
+			index:  1,
+		},
+		{
+			name:   "test_verify_reordered",
+			tamper: func(txs []Tx) []Tx { return []Tx{txs[0], txs[2], txs[1]} },
+			index:  1,
+		},
+		{
+			name: "test_verify_replaced",
+			tamper: func(txs []Tx) []Tx {
+				txs[2].Payload.Password = "replaced"
+				return txs
+			},
+			index: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := NewTxManager()
+			for idx := 0; idx < 3; idx++ {
+				if err := manager.AddTx(Entry{ID: uuid.New().String(), Title: fmt.Sprintf("title %d", idx)}); err != nil {
+					t.Fatalf("add tx: %v", err)
+				}
+			}
+
+			manager.txList = tc.tamper(manager.txList)
+			err := manager.Verify()
+			if tc.index < 0 {
+				if err != nil {
+					t.Fatalf("verify: %v", err)
+				}
+
+				return
+			}
+
+			var chainErr *ChainError
+			if !errors.As(err, &chainErr) || !errors.Is(err, ErrChainBroken) {
+				t.Fatalf("verify: %v, want %v", err, ErrChainBroken)
+			}
+
+			if diff := cmp.Diff(tc.index, chainErr.Index); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTxManager_VerifyTruncated(t *testing.T) {
+	t.Parallel()
+
+	manager := NewTxManager()
+	for idx := 0; idx < 3; idx++ {
+		if err := manager.AddTx(Entry{ID: uuid.New().String(), Title: fmt.Sprintf("title %d", idx)}); err != nil {
+			t.Fatalf("add tx: %v", err)
+		}
+	}
+
+	restored := NewTxManager()
+	restored.Deserialize(manager.Serialize())
+	if err := restored.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	// the log of the first two txs that still records the hash of the dropped last tx as its head
+	head := manager.txList[2].Hash
+	manager.txList = manager.txList[:2]
+	b := manager.Serialize()
+	list := gen.GetRootAsTxList(b, 0)
+	for idx := range head {
+		if !list.MutateHead(idx, head[idx]) {
+			t.Fatal("mutate head")
+		}
+	}
+
+	truncated := NewTxManager()
+	truncated.Deserialize(b)
+
+	var chainErr *ChainError
+	if err := truncated.Verify(); !errors.As(err, &chainErr) {
+		t.Fatalf("verify: %v, want %v", err, ErrChainBroken)
+	}
+
+	if diff := cmp.Diff(2, chainErr.Index); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err := truncated.AddTx(Entry{ID: uuid.New().String(), Title: "title"}); err != nil {
+		t.Fatalf("add tx: %v", err)
+	}
+
+	truncated.Commit()
+	if err := truncated.Verify(); err != nil {
+		t.Errorf("verify after commit: %v", err)
+	}
+}
+
+func TestTxManager_DeserializeLegacy(t *testing.T) {
+	t.Parallel()
+
+	manager := NewTxManager()
+	manager.txList = []Tx{
+		{Hash: []byte(`legacy 0`), Kind: TxKindAdd, Ts: time.Now().UTC(), Payload: Entry{ID: uuid.New().String()}},
+		{Hash: []byte(`legacy 1`), Kind: TxKindAdd, Ts: time.Now().UTC(), Payload: Entry{ID: uuid.New().String()}},
+	}
+
+	b := manager.Serialize()
+	if !gen.GetRootAsTxList(b, 0).MutateVersion(TxListVersionLegacy) {
+		t.Fatal("mutate version")
+	}
+
+	restored := NewTxManager()
+	restored.Deserialize(b)
+	if err := restored.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	if cmp.Equal([]byte(`legacy 0`), restored.txList[0].Hash) {
+		t.Error("legacy hash is not chained")
+	}
+
+	chained := NewTxManager()
+	chained.Deserialize(manager.Serialize())
+	if !errors.Is(chained.Verify(), ErrChainBroken) {
+		t.Error("chained log with independent hashes is verified")
+	}
+}
@@ -0,0 +1,215 @@
+// Package otp generates time-based one-time passwords as described in RFC 6238.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmSHA1   byte = 0x1
+	AlgorithmSHA256 byte = 0x2
+	AlgorithmSHA512 byte = 0x3
+)
+
+const (
+	defaultDigits = 6
+	defaultPeriod = 30 * time.Second
+)
+
+var (
+	ErrSecretNotValid      = errors.New("otp secret not valid")
+	ErrURINotValid         = errors.New("otpauth uri not valid")
+	ErrAlgorithmNotSupport = errors.New("otp algorithm not support")
+	ErrKeyNotValid         = errors.New("otp key not valid")
+)
+
+var algorithms = map[string]byte{
+	"SHA1":   AlgorithmSHA1,
+	"SHA256": AlgorithmSHA256,
+	"SHA512": AlgorithmSHA512,
+}
+
+// Key is a TOTP key. Digits is the length of a code and Period is the time a code is valid.
+type Key struct {
+	Secret    []byte
+	Algorithm byte
+	Digits    int
+	Period    time.Duration
+	Issuer    string
+	Account   string
+}
+
+// Parse parses an otpauth://totp URI or a base32 encoded seed. Seeds are case-insensitive,
+// spaces, dashes and padding are ignored. A seed has the SHA1 algorithm, 6 digits and a period of 30s
+// as the parameters missing in a URI.
+func Parse(s string) (Key, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return parseURI(s)
+	}
+
+	secret, err := decodeSecret(s)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: defaultDigits, Period: defaultPeriod}, nil
+}
+
+func parseURI(s string) (Key, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrURINotValid, err)
+	}
+
+	if !strings.EqualFold(u.Host, "totp") {
+		return Key{}, fmt.Errorf("%w: type %q, only totp is supported", ErrURINotValid, u.Host)
+	}
+
+	query := u.Query()
+	secret, err := decodeSecret(query.Get("secret"))
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: defaultDigits, Period: defaultPeriod}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := cutLabel(label); ok {
+		key.Issuer, key.Account = issuer, account
+	} else {
+		key.Account = label
+	}
+
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	if alg := query.Get("algorithm"); alg != "" {
+		id, ok := algorithms[strings.ToUpper(alg)]
+		if !ok {
+			return Key{}, fmt.Errorf("%w: %s", ErrAlgorithmNotSupport, alg)
+		}
+
+		key.Algorithm = id
+	}
+
+	if digits := query.Get("digits"); digits != "" {
+		key.Digits, err = strconv.Atoi(digits)
+		if err != nil || key.Digits < 6 || key.Digits > 8 {
+			return Key{}, fmt.Errorf("%w: digits %q, use 6 to 8", ErrURINotValid, digits)
+		}
+	}
+
+	if period := query.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil || seconds <= 0 {
+			return Key{}, fmt.Errorf("%w: period %q", ErrURINotValid, period)
+		}
+
+		key.Period = time.Duration(seconds) * time.Second
+	}
+
+	return key, nil
+}
+
+func cutLabel(label string) (issuer, account string, ok bool) {
+	if i := strings.Index(label, ":"); i >= 0 {
+		return label[:i], strings.TrimSpace(label[i+1:]), true
+	}
+
+	return "", label, false
+}
+
+func decodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSecretNotValid, err)
+	}
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrSecretNotValid)
+	}
+
+	return secret, nil
+}
+
+// Code returns the code of the key valid at t.
+func (k Key) Code(t time.Time) (string, error) {
+	if k.Period < time.Second || k.Digits < 1 {
+		return "", ErrKeyNotValid
+	}
+
+	h, err := hashFunc(k.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	counter := uint64(t.Unix()) / uint64(k.Period/time.Second)
+
+	return hotp(h, k.Secret, counter, k.Digits), nil
+}
+
+// Remaining returns how long the code at t stays valid.
+func (k Key) Remaining(t time.Time) time.Duration {
+	period := int64(k.Period / time.Second)
+
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// AlgorithmName returns the name of the algorithm as used in otpauth URIs.
+func AlgorithmName(alg byte) string {
+	for name, id := range algorithms {
+		if id == alg {
+			return name
+		}
+	}
+
+	return "unknown"
+}
+
+func hashFunc(alg byte) (func() hash.Hash, error) {
+	switch alg {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, ErrAlgorithmNotSupport
+	}
+}
+
+// hotp is the HOTP value of RFC 4226 with the dynamic truncation to digits.
+func hotp(h func() hash.Hash, secret []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(h, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,135 @@
+package otp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestKey_Code checks the test vectors of RFC 6238 appendix B.
+func TestKey_Code(t *testing.T) {
+	t.Parallel()
+
+	secrets := map[byte][]byte{
+		AlgorithmSHA1:   []byte("12345678901234567890"),
+		AlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		AlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	testCases := []struct {
+		name     string
+		ts       int64
+		alg      byte
+		expected string
+	}{
+		{name: "test_code_sha1_59", ts: 59, alg: AlgorithmSHA1, expected: "94287082"},
+		{name: "test_code_sha256_59", ts: 59, alg: AlgorithmSHA256, expected: "46119246"},
+		{name: "test_code_sha512_59", ts: 59, alg: AlgorithmSHA512, expected: "90693936"},
+		{name: "test_code_sha1_1111111109", ts: 1111111109, alg: AlgorithmSHA1, expected: "07081804"},
+		{name: "test_code_sha256_1111111109", ts: 1111111109, alg: AlgorithmSHA256, expected: "68084774"},
+		{name: "test_code_sha512_1111111109", ts: 1111111109, alg: AlgorithmSHA512, expected: "25091201"},
+		{name: "test_code_sha1_1111111111", ts: 1111111111, alg: AlgorithmSHA1, expected: "14050471"},
+		{name: "test_code_sha256_1111111111", ts: 1111111111, alg: AlgorithmSHA256, expected: "67062674"},
+		{name: "test_code_sha512_1111111111", ts: 1111111111, alg: AlgorithmSHA512, expected: "99943326"},
+		{name: "test_code_sha1_1234567890", ts: 1234567890, alg: AlgorithmSHA1, expected: "89005924"},
+		{name: "test_code_sha256_1234567890", ts: 1234567890, alg: AlgorithmSHA256, expected: "91819424"},
+		{name: "test_code_sha512_1234567890", ts: 1234567890, alg: AlgorithmSHA512, expected: "93441116"},
+		{name: "test_code_sha1_2000000000", ts: 2000000000, alg: AlgorithmSHA1, expected: "69279037"},
+		{name: "test_code_sha256_2000000000", ts: 2000000000, alg: AlgorithmSHA256, expected: "90698825"},
+		{name: "test_code_sha512_2000000000", ts: 2000000000, alg: AlgorithmSHA512, expected: "38618901"},
+		{name: "test_code_sha1_20000000000", ts: 20000000000, alg: AlgorithmSHA1, expected: "65353130"},
+		{name: "test_code_sha256_20000000000", ts: 20000000000, alg: AlgorithmSHA256, expected: "77737706"},
+		{name: "test_code_sha512_20000000000", ts: 20000000000, alg: AlgorithmSHA512, expected: "47863826"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := Key{Secret: secrets[tc.alg], Algorithm: tc.alg, Digits: 8, Period: 30 * time.Second}
+			code, err := key.Code(time.Unix(tc.ts, 0))
+			if err != nil {
+				t.Fatalf("code: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, code); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestKey_Remaining(t *testing.T) {
+	t.Parallel()
+
+	key := Key{Period: 30 * time.Second}
+	if diff := cmp.Diff(30*time.Second, key.Remaining(time.Unix(60, 0))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if diff := cmp.Diff(1*time.Second, key.Remaining(time.Unix(89, 0))); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	// GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ is the base32 of 12345678901234567890
+	secret := []byte("12345678901234567890")
+
+	testCases := []struct {
+		name     string
+		s        string
+		expected Key
+		err      error
+	}{
+		{
+			name:     "test_parse_seed",
+			s:        "gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+			expected: Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: 6, Period: 30 * time.Second},
+		},
+		{
+			name: "test_parse_uri",
+			s: "otpauth://totp/ACME%20Co:john@example.com?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" +
+				"&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			expected: Key{
+				Secret:    secret,
+				Algorithm: AlgorithmSHA256,
+				Digits:    8,
+				Period:    60 * time.Second,
+				Issuer:    "ACME Co",
+				Account:   "john@example.com",
+			},
+		},
+		{
+			name:     "test_parse_uri_defaults",
+			s:        "otpauth://totp/john?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			expected: Key{Secret: secret, Algorithm: AlgorithmSHA1, Digits: 6, Period: 30 * time.Second, Account: "john"},
+		},
+		{name: "test_parse_hotp", s: "otpauth://hotp/john?secret=GEZDGNBV&counter=1", err: ErrURINotValid},
+		{name: "test_parse_algorithm", s: "otpauth://totp/john?secret=GEZDGNBV&algorithm=MD5", err: ErrAlgorithmNotSupport},
+		{name: "test_parse_digits", s: "otpauth://totp/john?secret=GEZDGNBV&digits=10", err: ErrURINotValid},
+		{name: "test_parse_no_secret", s: "otpauth://totp/john", err: ErrSecretNotValid},
+		{name: "test_parse_seed_not_base32", s: "not a seed!", err: ErrSecretNotValid},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := Parse(tc.s)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("parse: %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.expected, key); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/polylab/mypass-cli/internal/manager"
+)
+
+// Scores of a fuzzy match, a match scores more when its characters are consecutive,
+// start words and start the text, every skipped character costs gapPenalty.
+const (
+	matchScore       = 16
+	consecutiveBonus = 24
+	wordStartBonus   = 32
+	textStartBonus   = 16
+	exactBonus       = 256
+	gapPenalty       = 1
+
+	noMatch = math.MinInt32
+)
+
+// Result is an entry matching a query with the score and the field of the best match.
+type Result struct {
+	Entry manager.Entry
+	Score int
+	Field string
+}
+
+// Field is a searchable field of an entry.
+type Field struct {
+	Name  string
+	Value func(e manager.Entry) []string
+}
+
+// Fields are the fields Search matches against.
+var Fields = []Field{
+	{Name: "title", Value: func(e manager.Entry) []string { return []string{e.Title} }},
+	{Name: "username", Value: func(e manager.Entry) []string { return []string{e.Username} }},
+	{Name: "url", Value: func(e manager.Entry) []string { return e.URLs }},
+	{Name: "folder", Value: func(e manager.Entry) []string { return []string{e.Folder} }},
+	{Name: "tag", Value: func(e manager.Entry) []string { return e.Tags }},
+}
+
+// Search returns entries matching query in any of Fields ranked by score, best first.
+// Entries with equal scores keep their order. An empty query matches all entries.
+func Search(entries []manager.Entry, query string) []Result {
+	results := make([]Result, 0)
+	for _, entry := range entries {
+		best, matched := Result{Entry: entry}, false
+		for _, field := range Fields {
+			for _, value := range field.Value(entry) {
+				if score, ok := Score(query, value); ok && (!matched || score > best.Score) {
+					best.Score, best.Field, matched = score, field.Name, true
+				}
+			}
+		}
+
+		if matched {
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// Score returns the score of the best fuzzy match of query in text. Characters of query must
+// appear in text in order, case is ignored. The match is false when query is not found.
+func Score(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	if len(q) > len(t) {
+		return 0, false
+	}
+
+	// best[i][j] is the best score of matching q[:i+1] with q[i] at t[j]
+	best := make([][]int, len(q))
+	for i := range best {
+		best[i] = make([]int, len(t))
+		for j := range best[i] {
+			best[i][j] = noMatch
+		}
+	}
+
+	for i := range q {
+		for j := i; j < len(t); j++ {
+			if q[i] != t[j] {
+				continue
+			}
+
+			bonus := matchScore
+			if j == 0 {
+				bonus += textStartBonus
+			}
+
+			if isWordStart(t, j) {
+				bonus += wordStartBonus
+			}
+
+			if i == 0 {
+				best[i][j] = bonus - j*gapPenalty
+				continue
+			}
+
+			for k := i - 1; k < j; k++ {
+				if best[i-1][k] == noMatch {
+					continue
+				}
+
+				score := best[i-1][k] + bonus - (j-k-1)*gapPenalty
+				if k == j-1 {
+					score += consecutiveBonus
+				}
+
+				if score > best[i][j] {
+					best[i][j] = score
+				}
+			}
+		}
+	}
+
+	score := noMatch
+	for _, s := range best[len(q)-1] {
+		if s > score {
+			score = s
+		}
+	}
+
+	if score == noMatch {
+		return 0, false
+	}
+
+	if strings.EqualFold(query, text) {
+		score += exactBonus
+	}
+
+	return score, true
+}
+
+// isWordStart reports if t[j] starts a word, that is it follows a separator such as / or space.
+func isWordStart(t []rune, j int) bool {
+	if j == 0 {
+		return true
+	}
+
+	return !unicode.IsLetter(t[j-1]) && !unicode.IsDigit(t[j-1])
+}
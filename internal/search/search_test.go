@@ -0,0 +1,118 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/polylab/mypass-cli/internal/manager"
+)
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		query string
+		text  string
+		ok    bool
+	}{
+		{name: "test_score_empty", query: "", text: "github", ok: true},
+		{name: "test_score_subsequence", query: "gthb", text: "github", ok: true},
+		{name: "test_score_case", query: "GitHub", text: "work/github", ok: true},
+		{name: "test_score_unicode", query: "пчт", text: "почта", ok: true},
+		{name: "test_score_order", query: "hg", text: "github", ok: false},
+		{name: "test_score_longer", query: "githubs", text: "github", ok: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, ok := Score(tc.query, tc.text)
+			if diff := cmp.Diff(tc.ok, ok); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		query    string
+		titles   []string
+		expected []string
+	}{
+		{
+			name:     "test_search_word_start",
+			query:    "gh",
+			titles:   []string{"lighthouse", "work/github", "bank", "github"},
+			expected: []string{"github", "work/github", "lighthouse"},
+		},
+		{
+			name:     "test_search_exact",
+			query:    "mail",
+			titles:   []string{"gmail", "my mail", "mail"},
+			expected: []string{"mail", "my mail", "gmail"},
+		},
+		{
+			name:     "test_search_empty",
+			query:    "",
+			titles:   []string{"b", "a"},
+			expected: []string{"b", "a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries := make([]manager.Entry, len(tc.titles))
+			for idx, title := range tc.titles {
+				entries[idx] = manager.Entry{Title: title}
+			}
+
+			results := Search(entries, tc.query)
+			got := make([]string, len(results))
+			for idx, result := range results {
+				got[idx] = result.Entry.Title
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSearch_Fields(t *testing.T) {
+	t.Parallel()
+
+	entries := []manager.Entry{
+		{Title: "mail", Username: "alice"},
+		{Title: "work", URLs: []string{"https://example.com", "https://github.com"}},
+		{Title: "bank"},
+	}
+
+	got := make([]string, 0)
+	for _, result := range Search(entries, "github") {
+		got = append(got, result.Entry.Title+":"+result.Field)
+	}
+
+	if diff := cmp.Diff([]string{"work:url"}, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	got = got[:0]
+	for _, result := range Search(entries, "alice") {
+		got = append(got, result.Entry.Title+":"+result.Field)
+	}
+
+	if diff := cmp.Diff([]string{"mail:username"}, got); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+}
@@ -0,0 +1,96 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/polylab/mypass-cli/internal/crypt"
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/store"
+)
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+type BackupInfo struct {
+	store.Backup
+	Entries int
+	LastTx  time.Time
+}
+
+// InspectBackup opens the n-th backup of the vault with the password.
+func InspectBackup(file string, n int, password string, opts ...Option) (BackupInfo, error) {
+	backup := store.BackupName(file, n)
+	info, err := os.Stat(backup)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return BackupInfo{}, ErrBackupNotFound
+		}
+
+		return BackupInfo{}, fmt.Errorf("stat: %w", err)
+	}
+
+	cipherFS, err := BlockCipherFor(backup, password, opts...)
+	if err != nil {
+		return BackupInfo{}, fmt.Errorf("block cipher for: %w", err)
+	}
+
+	if err = cipherFS.VerifyCipher(); err != nil {
+		if !errors.Is(err, crypt.ErrCryptFileEmpty) {
+			return BackupInfo{}, fmt.Errorf("verify secret: %w", err)
+		}
+	}
+
+	txManager := newTxManager(newOptions(opts...))
+	s, err := manager.NewStore(cipherFS, txManager)
+	if err != nil {
+		return BackupInfo{}, fmt.Errorf("new store: %w", err)
+	}
+
+	backupInfo := BackupInfo{
+		Backup:  store.Backup{Number: n, Filename: backup, ModTime: info.ModTime()},
+		Entries: len(s.List()),
+	}
+
+	if txs := txManager.List(); len(txs) > 0 {
+		backupInfo.LastTx = txs[len(txs)-1].Ts
+	}
+
+	return backupInfo, nil
+}
+
+// RestoreBackup replaces the vault with its n-th backup once the backup is opened with the password.
+// The replaced vault is kept as the most recent backup.
+func RestoreBackup(file string, n int, password string, opts ...Option) (err error) {
+	options := newOptions(opts...)
+
+	sysFS := store.NewFS(file, store.WithBackups(options.backups))
+	if locker, ok := sysFS.(crypt.Locker); ok {
+		unlock, err := locker.Lock()
+		if err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+
+		defer func() {
+			if unlockErr := unlock(); unlockErr != nil && err == nil {
+				err = fmt.Errorf("unlock: %w", unlockErr)
+			}
+		}()
+	}
+
+	if _, err = InspectBackup(file, n, password, opts...); err != nil {
+		return fmt.Errorf("inspect backup: %w", err)
+	}
+
+	b, err := os.ReadFile(store.BackupName(file, n))
+	if err != nil {
+		return fmt.Errorf("read backup: %w", err)
+	}
+
+	if err = sysFS.Write(b); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package setup
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/store"
+)
+
+func TestRestoreBackup(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "db.bin")
+	opts := []Option{WithKDF(testKDF), WithBackups(2)}
+
+	s, err := Provide(file, "password", opts...)
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err = s.Add(manager.Entry{
+			ID:        uuid.New().String(),
+			Title:     "title",
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	backups, err := store.ListBackups(file)
+	if err != nil {
+		t.Fatalf("list backups: %v", err)
+	}
+
+	if diff := cmp.Diff(2, len(backups)); diff != "" {
+		t.Fatalf("diff (+got, -want): %s", diff)
+	}
+
+	for n, entries := range map[int]int{1: 3, 2: 2} {
+		info, err := InspectBackup(file, n, "password", opts...)
+		if err != nil {
+			t.Fatalf("inspect backup %d: %v", n, err)
+		}
+
+		if diff := cmp.Diff(entries, info.Entries); diff != "" {
+			t.Errorf("backup %d diff (+got, -want): %s", n, diff)
+		}
+	}
+
+	if _, err = InspectBackup(file, 3, "password", opts...); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("inspect backup 3: got %v, want %v", err, ErrBackupNotFound)
+	}
+
+	if err = RestoreBackup(file, 2, "password", opts...); err != nil {
+		t.Fatalf("restore backup: %v", err)
+	}
+
+	s, err = Provide(file, "password", opts...)
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	if diff := cmp.Diff(2, len(s.List())); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	info, err := InspectBackup(file, 1, "password", opts...)
+	if err != nil {
+		t.Fatalf("inspect backup: %v", err)
+	}
+
+	if diff := cmp.Diff(4, info.Entries); diff != "" {
+		t.Errorf("replaced vault diff (+got, -want): %s", diff)
+	}
+}
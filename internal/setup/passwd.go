@@ -0,0 +1,221 @@
+package setup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/polylab/mypass-cli/internal/crypt"
+	"github.com/polylab/mypass-cli/internal/store"
+)
+
+var ErrRewriteNotVerified = errors.New("rewritten vault not verified")
+
+// ChangePassword re-encrypts the vault with a key derived from newPassword.
+// The vault is rewritten into a temporary file that is verified by re-opening it
+// before it replaces the original. A copy of the original file is kept next to it
+// until the replaced vault is verified again. The backups of the vault are re-encrypted with the new key
+// once the vault is replaced, the backups that oldPassword does not open are kept as they are and returned.
+// The vault is locked for the whole rewrite.
+func ChangePassword(file, oldPassword, newPassword string, opts ...Option) (skipped []store.Backup, err error) {
+	options := newOptions(opts...)
+
+	if locker, ok := store.NewFS(file).(crypt.Locker); ok {
+		unlock, err := locker.Lock()
+		if err != nil {
+			return nil, fmt.Errorf("lock: %w", err)
+		}
+
+		defer func() {
+			if unlockErr := unlock(); unlockErr != nil && err == nil {
+				err = fmt.Errorf("unlock: %w", unlockErr)
+			}
+		}()
+	}
+
+	current, err := BlockCipherFor(file, oldPassword, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("block cipher for: %w", err)
+	}
+
+	if err = current.VerifyCipher(); err != nil {
+		if !errors.Is(err, crypt.ErrCryptFileEmpty) {
+			return nil, fmt.Errorf("verify secret: %w", err)
+		}
+	}
+
+	plain, err := current.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	txManager := newTxManager(options)
+	txManager.Deserialize(plain)
+	payload := txManager.Serialize()
+
+	raw, err := store.NewFS(file).Open()
+	if err != nil {
+		return nil, fmt.Errorf("fs: %w", err)
+	}
+
+	typ, err := rewriteCipher(raw, options)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := file + ".bak"
+	if err = os.WriteFile(backup, raw, 0600); err != nil {
+		return nil, fmt.Errorf("write backup: %w", err)
+	}
+
+	tmp := file + ".tmp"
+	if err = rewrite(tmp, newPassword, typ, payload, opts...); err != nil {
+		_ = os.Remove(tmp)
+
+		return nil, err
+	}
+
+	if err = os.Rename(tmp, file); err != nil {
+		_ = os.Remove(tmp)
+
+		return nil, fmt.Errorf("replace vault: %w", err)
+	}
+
+	if err = store.SyncDir(filepath.Dir(file)); err != nil {
+		return nil, fmt.Errorf("dir sync: %w", err)
+	}
+
+	if err = verifyRewrite(file, newPassword, payload, opts...); err != nil {
+		if restoreErr := os.Rename(backup, file); restoreErr != nil {
+			return nil, fmt.Errorf("%v, restore backup %s: %w", err, backup, restoreErr)
+		}
+
+		return nil, err
+	}
+
+	if err = os.Remove(backup); err != nil {
+		return nil, fmt.Errorf("remove backup: %w", err)
+	}
+
+	return rewriteBackups(file, oldPassword, newPassword, opts...)
+}
+
+// rewriteCipher returns the cipher of the rewritten vault, the one of the options or the one of raw.
+func rewriteCipher(raw []byte, options Options) (byte, error) {
+	typ, err := aeadCipherFor(options.alg)
+	if err != nil {
+		return 0, err
+	}
+
+	if options.alg == "" && crypt.IsHeader(raw) {
+		header, _, err := crypt.ParseHeader(raw)
+		if err != nil {
+			return 0, fmt.Errorf("parse header: %w", err)
+		}
+		typ = header.Cipher
+	}
+
+	return typ, nil
+}
+
+// rewriteBackups re-encrypts the backups of the vault with a key derived from newPassword, so they are
+// restored with the new password and the old one opens none of them. Backups that the old password
+// does not open, such as ones left by an interrupted change of an earlier password, are skipped and returned.
+func rewriteBackups(file, oldPassword, newPassword string, opts ...Option) ([]store.Backup, error) {
+	options := newOptions(opts...)
+
+	backups, err := store.ListBackups(file)
+	if err != nil {
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+
+	var skipped []store.Backup
+
+	for _, b := range backups {
+		raw, err := os.ReadFile(b.Filename)
+		if err != nil {
+			return skipped, fmt.Errorf("read backup %d: %w", b.Number, err)
+		}
+
+		current, err := BlockCipherFor(b.Filename, oldPassword, opts...)
+		if err != nil {
+			return skipped, fmt.Errorf("backup %d: block cipher for: %w", b.Number, err)
+		}
+
+		if err = current.VerifyCipher(); err != nil {
+			if !errors.Is(err, crypt.ErrSecretNotValid) {
+				return skipped, fmt.Errorf("backup %d: verify secret: %w", b.Number, err)
+			}
+
+			skipped = append(skipped, b)
+
+			continue
+		}
+
+		plain, err := current.Open()
+		if err != nil {
+			return skipped, fmt.Errorf("backup %d: open: %w", b.Number, err)
+		}
+
+		typ, err := rewriteCipher(raw, options)
+		if err != nil {
+			return skipped, fmt.Errorf("backup %d: %w", b.Number, err)
+		}
+
+		tmp := b.Filename + ".tmp"
+		if err = rewrite(tmp, newPassword, typ, plain, opts...); err != nil {
+			_ = os.Remove(tmp)
+
+			return skipped, fmt.Errorf("backup %d: %w", b.Number, err)
+		}
+
+		if err = os.Rename(tmp, b.Filename); err != nil {
+			_ = os.Remove(tmp)
+
+			return skipped, fmt.Errorf("replace backup %d: %w", b.Number, err)
+		}
+
+		if err = store.SyncDir(filepath.Dir(b.Filename)); err != nil {
+			return skipped, fmt.Errorf("dir sync: %w", err)
+		}
+	}
+
+	return skipped, nil
+}
+
+func rewrite(file, password string, typ byte, payload []byte, opts ...Option) error {
+	fs, err := saltedFS(typ, newOptions(opts...).kdf, store.NewFS(file), password)
+	if err != nil {
+		return fmt.Errorf("make aead fs: %w", err)
+	}
+
+	if err = fs.Write(payload); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return verifyRewrite(file, password, payload, opts...)
+}
+
+func verifyRewrite(file, password string, payload []byte, opts ...Option) error {
+	fs, err := BlockCipherFor(file, password, opts...)
+	if err != nil {
+		return fmt.Errorf("block cipher for: %w", err)
+	}
+
+	if err = fs.VerifyCipher(); err != nil {
+		return fmt.Errorf("verify secret: %w", err)
+	}
+
+	b, err := fs.Open()
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	if !bytes.Equal(b, payload) {
+		return ErrRewriteNotVerified
+	}
+
+	return nil
+}
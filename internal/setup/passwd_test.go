@@ -0,0 +1,154 @@
+package setup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/polylab/mypass-cli/internal/crypt"
+	"github.com/polylab/mypass-cli/internal/manager"
+	"github.com/polylab/mypass-cli/internal/store"
+)
+
+var testKDF = crypt.KDFParams{ID: crypt.KDFArgon2id, Time: 1, Memory: 1024, Threads: 1}
+
+func TestChangePassword(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "db.bin")
+	store, err := Provide(file, "old", WithKDF(testKDF))
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	entry := manager.Entry{
+		ID:        uuid.New().String(),
+		Title:     "title",
+		Password:  "password",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err = store.Add(entry); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	if _, err = ChangePassword(file, "old", "new", WithKDF(testKDF), WithXChaCha20()); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+
+	if _, err = Provide(file, "old", WithKDF(testKDF)); !errors.Is(err, crypt.ErrSecretNotValid) {
+		t.Errorf("provide with old password: got %v, want %v", err, crypt.ErrSecretNotValid)
+	}
+
+	store, err = Provide(file, "new", WithKDF(testKDF))
+	if err != nil {
+		t.Fatalf("provide with new password: %v", err)
+	}
+
+	if diff := cmp.Diff([]manager.Entry{entry}, store.List()); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	for _, name := range []string{file + ".bak", file + ".tmp"} {
+		if _, err = os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed: %v", name, err)
+		}
+	}
+}
+
+func TestChangePassword_Backups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "db.bin")
+	opts := []Option{WithKDF(testKDF), WithBackups(2)}
+
+	s, err := Provide(file, "old", opts...)
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err = s.Add(manager.Entry{ID: uuid.New().String(), Title: "title"}); err != nil {
+			t.Fatalf("store add: %v", err)
+		}
+	}
+
+	// a backup left from an earlier main password
+	other := filepath.Join(dir, "other.bin")
+	s, err = Provide(other, "older", opts...)
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	if err = s.Add(manager.Entry{ID: uuid.New().String(), Title: "title"}); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	if err = os.Rename(other, store.BackupName(file, 3)); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	skipped, err := ChangePassword(file, "old", "new", opts...)
+	if err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{3}, backupNumbers(skipped)); diff != "" {
+		t.Errorf("skipped diff (+got, -want): %s", diff)
+	}
+
+	for n, entries := range map[int]int{1: 2, 2: 1} {
+		if _, err = InspectBackup(file, n, "old", opts...); !errors.Is(err, crypt.ErrSecretNotValid) {
+			t.Errorf("inspect backup %d with old password: got %v, want %v", n, err, crypt.ErrSecretNotValid)
+		}
+
+		info, err := InspectBackup(file, n, "new", opts...)
+		if err != nil {
+			t.Fatalf("inspect backup %d with new password: %v", n, err)
+		}
+
+		if diff := cmp.Diff(entries, info.Entries); diff != "" {
+			t.Errorf("backup %d diff (+got, -want): %s", n, diff)
+		}
+	}
+
+	if _, err = InspectBackup(file, 3, "older", opts...); err != nil {
+		t.Errorf("inspect skipped backup 3 with its password: %v", err)
+	}
+}
+
+func backupNumbers(backups []store.Backup) []int {
+	numbers := make([]int, len(backups))
+	for idx, backup := range backups {
+		numbers[idx] = backup.Number
+	}
+
+	return numbers
+}
+
+func TestChangePassword_WrongPassword(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "db.bin")
+	store, err := Provide(file, "old", WithKDF(testKDF))
+	if err != nil {
+		t.Fatalf("provide: %v", err)
+	}
+
+	if err = store.Add(manager.Entry{ID: uuid.New().String(), Title: "title"}); err != nil {
+		t.Fatalf("store add: %v", err)
+	}
+
+	if _, err = ChangePassword(file, "wrong", "new", WithKDF(testKDF)); !errors.Is(err, crypt.ErrSecretNotValid) {
+		t.Errorf("change password: got %v, want %v", err, crypt.ErrSecretNotValid)
+	}
+
+	if _, err = Provide(file, "old", WithKDF(testKDF)); err != nil {
+		t.Errorf("provide with old password: %v", err)
+	}
+}
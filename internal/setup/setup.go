@@ -9,83 +9,153 @@ import (
 	"github.com/polylab/mypass-cli/internal/store"
 )
 
-func BlockCipherFor(alg, file, password string) (crypt.CipherFS, error) {
-	var cipherFS crypt.CipherFS
+// BlockCipherFor returns CipherFS for the vault file. The file header decides how the data is read,
+// the options decide how it is written. A vault whose cipher or kdf differs from the options
+// is re-encrypted with a fresh salt on the next write.
+func BlockCipherFor(file, password string, opts ...Option) (crypt.CipherFS, error) {
+	options := newOptions(opts...)
 
-	switch alg {
-	case "aes":
-		fs, err := aesFS(file, password)
+	typ, err := aeadCipherFor(options.alg)
+	if err != nil {
+		return nil, err
+	}
+
+	sysFS := store.NewFS(file, store.WithBackups(options.backups))
+	b, err := sysFS.Open()
+	if err != nil {
+		return nil, fmt.Errorf("fs: %w", err)
+	}
+
+	if len(b) == 0 {
+		fs, err := saltedFS(typ, options.kdf, sysFS, password)
 		if err != nil {
-			return nil, fmt.Errorf("make aes fs: %w", err)
+			return nil, fmt.Errorf("make aead fs: %w", err)
 		}
-		cipherFS = fs
-	case "des":
-		fs, err := desFS(file, password)
+
+		return fs, nil
+	}
+
+	var reader crypt.CipherFS
+	if crypt.IsHeader(b) {
+		header, _, err := crypt.ParseHeader(b)
 		if err != nil {
-			return nil, fmt.Errorf("make des fs: %w", err)
+			return nil, fmt.Errorf("parse header: %w", err)
 		}
-		cipherFS = fs
-	default:
-		fs := store.NewFS(file)
-		b, err := fs.Open()
+
+		fs, err := aeadFS(header.Cipher, header.KDF, header.Salt, sysFS, password)
 		if err != nil {
-			return nil, fmt.Errorf("fs: %w", err)
+			return nil, fmt.Errorf("make aead fs: %w", err)
 		}
 
-		if len(b) == 0 {
-			fs, err := aesFS(file, password)
-			if err != nil {
-				return nil, fmt.Errorf("make aes fs: %w", err)
-			}
-			cipherFS = fs
-			break
+		if options.alg == "" {
+			typ = header.Cipher
 		}
 
+		if header.Cipher == typ && header.KDF == options.kdf {
+			return fs, nil
+		}
+		reader = fs
+	} else {
+		// Vaults written before the header was introduced start with the cipher type byte.
 		switch b[0] {
+		case crypt.CipherAEADAES256GCM, crypt.CipherAEADXChaCha20:
+			if options.alg == "" {
+				typ = b[0]
+			}
+
+			fs, err := aeadFS(b[0], crypt.PBKDF2Params(), nil, sysFS, password)
+			if err != nil {
+				return nil, fmt.Errorf("make aead fs: %w", err)
+			}
+			reader = fs
 		case crypt.CipherBlockAES:
-			fs, err := aesFS(file, password)
+			fs, err := aesFS(sysFS, password)
 			if err != nil {
 				return nil, fmt.Errorf("make aes fs: %w", err)
 			}
-			cipherFS = fs
+			reader = fs
 		case crypt.CipherBlockDES:
-			fs, err := desFS(file, password)
+			fs, err := desFS(sysFS, password)
 			if err != nil {
 				return nil, fmt.Errorf("make des fs: %w", err)
 			}
-			cipherFS = fs
+			reader = fs
 		default:
 			return nil, crypt.ErrCipherBlockNotSupport
 		}
 	}
 
-	return cipherFS, nil
+	writer, err := saltedFS(typ, options.kdf, sysFS, password)
+	if err != nil {
+		return nil, fmt.Errorf("make aead fs: %w", err)
+	}
+
+	return crypt.NewMigrateFS(reader, writer), nil
+}
+
+func aeadCipherFor(alg string) (byte, error) {
+	switch alg {
+	case "", "aes":
+		return crypt.CipherAEADAES256GCM, nil
+	case "xchacha20":
+		return crypt.CipherAEADXChaCha20, nil
+	default:
+		return 0, fmt.Errorf("unknown cipher %q: %w", alg, crypt.ErrCipherBlockNotSupport)
+	}
+}
+
+func aeadFS(typ byte, kdf crypt.KDFParams, salt []byte, sysFS store.FS, password string) (crypt.CipherFS, error) {
+	key, err := crypt.DeriveKey(password, kdf, salt, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	newFn := crypt.NewAESGCM
+	if typ == crypt.CipherAEADXChaCha20 {
+		newFn = crypt.NewXChaCha20
+	}
+
+	fs, err := newFn(key, sysFS, crypt.WithKDF(kdf, salt))
+	if err != nil {
+		return nil, fmt.Errorf("new aead crypt: %w", err)
+	}
+
+	return fs, nil
 }
 
-func aesFS(file, password string) (crypt.CipherFS, error) {
+func saltedFS(typ byte, kdf crypt.KDFParams, sysFS store.FS, password string) (crypt.CipherFS, error) {
+	salt, err := crypt.NewSalt()
+	if err != nil {
+		return nil, fmt.Errorf("new salt: %w", err)
+	}
+
+	return aeadFS(typ, kdf, salt, sysFS, password)
+}
+
+func aesFS(sysFS store.FS, password string) (crypt.CipherFS, error) {
 	genKeyFn := crypt.GeneratePrivateKeyAES()
 	key, err := genKeyFn(password)
 	if err != nil {
 		return nil, fmt.Errorf("generate private key: %w", err)
 	}
 
-	fs, err := crypt.NewAES(key, store.NewFS(file))
+	fs, err := crypt.NewAES(key, sysFS)
 	if err != nil {
 		return nil, fmt.Errorf("new aes crypt: %w", err)
 	}
 	return fs, nil
 }
 
-func desFS(file, password string) (crypt.CipherFS, error) {
+func desFS(sysFS store.FS, password string) (crypt.CipherFS, error) {
 	genKeyFn := crypt.GeneratePrivateKeyDES()
 	key, err := genKeyFn(password)
 	if err != nil {
 		return nil, fmt.Errorf("generate private key: %w", err)
 	}
 
-	fs, err := crypt.NewAES(key, store.NewFS(file))
+	fs, err := crypt.NewDES(key, sysFS)
 	if err != nil {
-		return nil, fmt.Errorf("new aes crypt: %w", err)
+		return nil, fmt.Errorf("new des crypt: %w", err)
 	}
 
 	return fs, nil
@@ -94,7 +164,22 @@ func desFS(file, password string) (crypt.CipherFS, error) {
 type Option func(*Options)
 
 type Options struct {
-	alg string
+	alg     string
+	kdf     crypt.KDFParams
+	backups int
+	txHash  byte
+	// compactThreshold and compact are passed to manager.WithCompaction
+	compactThreshold int
+	compact          []manager.CompactOption
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{kdf: crypt.Argon2idParams()}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
 }
 
 func WithAES() Option {
@@ -103,20 +188,52 @@ func WithAES() Option {
 	}
 }
 
-func WithDES() Option {
+func WithXChaCha20() Option {
 	return func(options *Options) {
-		options.alg = "des"
+		options.alg = "xchacha20"
 	}
 }
 
-func Provide(file, password string, opts ...Option) (*manager.Store, error) {
-	var options Options
+// WithKDF set the key derivation the vault is written with
+func WithKDF(params crypt.KDFParams) Option {
+	return func(options *Options) {
+		options.kdf = params
+	}
+}
 
-	for _, o := range opts {
-		o(&options)
+// WithBackups set how many previous versions of the vault are kept on every write
+func WithBackups(n int) Option {
+	return func(options *Options) {
+		options.backups = n
+	}
+}
+
+// WithTxHash set the hash algorithm of the tx log of a new vault
+func WithTxHash(alg byte) Option {
+	return func(options *Options) {
+		options.txHash = alg
+	}
+}
+
+// WithCompaction set the tx log to be compacted when more than threshold txs follow its snapshot
+func WithCompaction(threshold int, opts ...manager.CompactOption) Option {
+	return func(options *Options) {
+		options.compactThreshold = threshold
+		options.compact = opts
 	}
+}
 
-	cipherFor, err := BlockCipherFor(options.alg, file, password)
+func newTxManager(options Options) *manager.TxManager {
+	opts := []manager.Option{manager.WithCompaction(options.compactThreshold, options.compact...)}
+	if options.txHash != 0 {
+		opts = append(opts, manager.WithHash(options.txHash))
+	}
+
+	return manager.NewTxManager(opts...)
+}
+
+func Provide(file, password string, opts ...Option) (*manager.Store, error) {
+	cipherFor, err := BlockCipherFor(file, password, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("block sipher for: %w", err)
 	}
@@ -127,7 +244,7 @@ func Provide(file, password string, opts ...Option) (*manager.Store, error) {
 		}
 	}
 
-	m, err := manager.NewStore(cipherFor, manager.NewTxManager())
+	m, err := manager.NewStore(cipherFor, newTxManager(newOptions(opts...)))
 	if err != nil {
 		return nil, fmt.Errorf("new store: %w", err)
 	}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Backup struct {
+	Number   int
+	Filename string
+	ModTime  time.Time
+}
+
+// BackupName returns the name of the n-th backup of filename, the most recent backup is 1.
+func BackupName(filename string, n int) string {
+	return filename + "." + strconv.Itoa(n)
+}
+
+// ListBackups returns backups of filename ordered from the most recent one.
+func ListBackups(filename string) ([]Backup, error) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir: %w", err)
+	}
+
+	backups := make([]Backup, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), base+".") {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), base+"."))
+		if err != nil || n < 1 {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat: %w", err)
+		}
+
+		backups = append(backups, Backup{Number: n, Filename: BackupName(filename, n), ModTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Number < backups[j].Number
+	})
+
+	return backups, nil
+}
+
+// rotateBackups shifts backups by one, dropping the oldest, and keeps the current content as backup 1.
+func (f fs) rotateBackups() error {
+	if f.opts.backups <= 0 {
+		return nil
+	}
+
+	current, err := os.ReadFile(f.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	if len(current) == 0 {
+		return nil
+	}
+
+	for n := f.opts.backups - 1; n >= 1; n-- {
+		if err = os.Rename(BackupName(f.filename, n), BackupName(f.filename, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rename backup: %w", err)
+		}
+	}
+
+	if err = writeSynced(BackupName(f.filename, 1), current); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+
+	return nil
+}
+
+func writeSynced(filename string, b []byte) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(b); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
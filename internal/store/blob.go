@@ -0,0 +1,126 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrBlobNotFound  = errors.New("blob not found")
+	ErrBlobCorrupted = errors.New("blob is corrupted")
+)
+
+// BlobDir is a content-addressed directory, a blob is named by the hex SHA-256 of its content,
+// so a blob is written once however many times it is put.
+type BlobDir struct {
+	dir string
+}
+
+func NewBlobDir(dir string) BlobDir {
+	return BlobDir{dir: dir}
+}
+
+// Put writes b unless the blob already exists and returns its name.
+// The blob is written to a temporary file and renamed, a failed write never leaves a partial blob.
+func (d BlobDir) Put(b []byte) (string, error) {
+	sum := sha256.Sum256(b)
+	name := hex.EncodeToString(sum[:])
+	if _, err := os.Stat(d.path(name)); err == nil {
+		return name, nil
+	}
+
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return "", fmt.Errorf("create dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.dir, name+".tmp*")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+
+	if err = writeTemp(tmp, b, nil); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return "", err
+	}
+
+	if err = os.Rename(tmp.Name(), d.path(name)); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return "", fmt.Errorf("rename temp file: %w", err)
+	}
+
+	if err = SyncDir(d.dir); err != nil {
+		return "", fmt.Errorf("dir sync: %w", err)
+	}
+
+	return name, nil
+}
+
+// Get returns the blob with the name, a blob whose content does not match its name is ErrBlobCorrupted.
+func (d BlobDir) Get(name string) ([]byte, error) {
+	b, err := os.ReadFile(d.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", name, ErrBlobNotFound)
+		}
+
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	if hex.EncodeToString(sum[:]) != name {
+		return nil, fmt.Errorf("%s: %w", name, ErrBlobCorrupted)
+	}
+
+	return b, nil
+}
+
+// Has reports whether the blob with the name exists.
+func (d BlobDir) Has(name string) bool {
+	_, err := os.Stat(d.path(name))
+
+	return err == nil
+}
+
+// Names returns the names of the blobs in the directory, temporary files are skipped.
+func (d BlobDir) Names() ([]string, error) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read dir: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if b, err := hex.DecodeString(file.Name()); err == nil && len(b) == sha256.Size && file.Type().IsRegular() {
+			names = append(names, file.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Remove removes the blob with the name.
+func (d BlobDir) Remove(name string) error {
+	if err := os.Remove(d.path(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", name, ErrBlobNotFound)
+		}
+
+		return fmt.Errorf("remove file: %w", err)
+	}
+
+	return nil
+}
+
+func (d BlobDir) path(name string) string {
+	return filepath.Join(d.dir, filepath.Base(name))
+}
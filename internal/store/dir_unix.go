@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package store
+
+import "os"
+
+// SyncDir syncs the directory so the renames of files in it are durable.
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	if err = d.Sync(); err != nil {
+		_ = d.Close()
+
+		return err
+	}
+
+	return d.Close()
+}
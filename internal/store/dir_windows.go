@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package store
+
+// SyncDir is a no-op, directories can not be opened for syncing on windows.
+func SyncDir(string) error {
+	return nil
+}
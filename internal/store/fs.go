@@ -3,6 +3,8 @@ package store
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 type FS interface {
@@ -10,12 +12,39 @@ type FS interface {
 	Write(b []byte) error
 }
 
-func NewFS(filename string) FS {
-	return fs{filename: filename}
+type Option func(*Options)
+
+type Options struct {
+	lockTimeout time.Duration
+	backups     int
+}
+
+// WithBackups set how many previous versions of the file Write keeps as filename.1 .. filename.n
+func WithBackups(n int) Option {
+	return func(options *Options) {
+		options.backups = n
+	}
+}
+
+// WithLockTimeout set how long Lock waits for the vault held by another process
+func WithLockTimeout(d time.Duration) Option {
+	return func(options *Options) {
+		options.lockTimeout = d
+	}
+}
+
+func NewFS(filename string, opts ...Option) FS {
+	f := fs{filename: filename, opts: Options{lockTimeout: defaultLockTimeout}}
+	for _, o := range opts {
+		o(&f.opts)
+	}
+
+	return f
 }
 
 type fs struct {
 	filename string
+	opts     Options
 }
 
 func (f fs) Open() ([]byte, error) {
@@ -43,8 +72,73 @@ func (f fs) Open() ([]byte, error) {
 }
 
 func (f fs) Write(b []byte) error {
-	if err := os.WriteFile(f.filename, b, 0600); err != nil {
-		return fmt.Errorf("failed to create object: %w", err)
+	return f.WriteVerify(b, nil)
+}
+
+// WriteVerify writes b to a temporary file in the same directory and syncs it.
+// The temporary file is read back and passed to verify, only then the backups are rotated,
+// it is renamed over the original and the directory is synced,
+// so a failed write never leaves a half-written file behind.
+func (f fs) WriteVerify(b []byte, verify func(b []byte) error) error {
+	dir := filepath.Dir(f.filename)
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	if err = writeTemp(tmp, b, verify); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err = f.rotateBackups(); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("rotate backups: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), f.filename); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	if err = SyncDir(dir); err != nil {
+		return fmt.Errorf("dir sync: %w", err)
+	}
+
+	return nil
+}
+
+func writeTemp(tmp *os.File, b []byte, verify func(b []byte) error) error {
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("temp file sync: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	if verify == nil {
+		return nil
+	}
+
+	written, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("read temp file: %w", err)
+	}
+
+	if err = verify(written); err != nil {
+		return fmt.Errorf("verify temp file: %w", err)
 	}
 
 	return nil
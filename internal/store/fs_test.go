@@ -0,0 +1,84 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFS_WriteVerify(t *testing.T) {
+	t.Parallel()
+
+	errVerify := errors.New("verify")
+
+	testCases := []struct {
+		name     string
+		data     []byte
+		verify   func(b []byte) error
+		expected []byte
+		err      error
+	}{
+		{
+			name:     "test_write_verified",
+			data:     []byte("new data"),
+			verify:   func(b []byte) error { return nil },
+			expected: []byte("new data"),
+		},
+		{
+			name:     "test_write_not_verified",
+			data:     []byte("new data"),
+			verify:   func(b []byte) error { return errVerify },
+			expected: []byte("old data"),
+			err:      errVerify,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "db.bin")
+			if err := os.WriteFile(filename, []byte("old data"), 0600); err != nil {
+				t.Fatalf("write file: %v", err)
+			}
+
+			var verified []byte
+			fs := fs{filename: filename}
+			err := fs.WriteVerify(tc.data, func(b []byte) error {
+				verified = b
+
+				return tc.verify(b)
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("write verify: got %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.data, verified); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			b, err := fs.Open()
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, b); diff != "" {
+				t.Errorf("diff (+got, -want): %s", diff)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("read dir: %v", err)
+			}
+
+			if diff := cmp.Diff(1, len(entries)); diff != "" {
+				t.Errorf("temp file left, diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLockTimeout = 5 * time.Second
+	lockRetryInterval  = 50 * time.Millisecond
+)
+
+var ErrLocked = errors.New("vault is locked")
+
+// LockedError is returned when the vault lock is not acquired within the timeout.
+type LockedError struct {
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return ErrLocked.Error()
+	}
+
+	return fmt.Sprintf("%s by PID %d", ErrLocked, e.PID)
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Lock takes an exclusive advisory lock on the vault for a read-modify-write cycle.
+// The lock is held on a separate file next to the vault, the PID of the holder is written into it.
+func (f fs) Lock() (func() error, error) {
+	file, err := os.OpenFile(f.filename+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(f.opts.lockTimeout)
+	for {
+		ok, err := tryLock(file)
+		if err != nil {
+			_ = file.Close()
+
+			return nil, fmt.Errorf("lock: %w", err)
+		}
+
+		if ok {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			pid := lockHolder(file)
+			_ = file.Close()
+
+			return nil, &LockedError{PID: pid}
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+
+	if err = writeLockHolder(file); err != nil {
+		_ = unlock(file)
+		_ = file.Close()
+
+		return nil, fmt.Errorf("write lock holder: %w", err)
+	}
+
+	return func() error {
+		if err := unlock(file); err != nil {
+			_ = file.Close()
+
+			return fmt.Errorf("unlock: %w", err)
+		}
+
+		return file.Close()
+	}, nil
+}
+
+func writeLockHolder(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func lockHolder(file *os.File) int {
+	b := make([]byte, 16)
+	n, _ := file.ReadAt(b, 0)
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b[:n])))
+	if err != nil {
+		return 0
+	}
+
+	return pid
+}
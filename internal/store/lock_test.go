@@ -0,0 +1,51 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFS_Lock(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "db.bin")
+	fs0 := NewFS(filename, WithLockTimeout(100*time.Millisecond)).(fs)
+	fs1 := NewFS(filename, WithLockTimeout(100*time.Millisecond)).(fs)
+
+	unlock, err := fs0.Lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	_, err = fs1.Lock()
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("lock held: got %v, want %v", err, ErrLocked)
+	}
+
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("lock held: got %T, want %T", err, lockedErr)
+	}
+
+	if diff := cmp.Diff(os.Getpid(), lockedErr.PID); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if err = unlock(); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+
+	unlock, err = fs1.Lock()
+	if err != nil {
+		t.Fatalf("lock released: %v", err)
+	}
+
+	if err = unlock(); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+// +build windows
+
+package store
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset puts the locked byte range past the PID so the holder stays readable.
+const lockOffset = 1 << 20
+
+func tryLock(file *os.File) (bool, error) {
+	ol := windows.Overlapped{Offset: lockOffset}
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if err := windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, &ol); err != nil {
+		if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func unlock(file *os.File) error {
+	ol := windows.Overlapped{Offset: lockOffset}
+
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &ol)
+}
@@ -0,0 +1,92 @@
+package strength
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The frequency lists of the most common passwords, English words from subtitles and US census names ordered
+// by frequency come from zxcvbn, https://github.com/dropbox/zxcvbn, licensed under the MIT license.
+var (
+	//go:embed passwords.txt
+	passwordsList string
+	//go:embed english.txt
+	englishList string
+	//go:embed female_names.txt
+	femaleNamesList string
+	//go:embed male_names.txt
+	maleNamesList string
+	//go:embed surnames.txt
+	surnamesList string
+)
+
+// Names of the dictionaries.
+const (
+	DictionaryPasswords   = "passwords"
+	DictionaryEnglish     = "english"
+	DictionaryFemaleNames = "female_names"
+	DictionaryMaleNames   = "male_names"
+	DictionarySurnames    = "surnames"
+	DictionaryUserInputs  = "user_inputs"
+)
+
+// dictionary maps lower case words to their frequency rank starting at 1.
+type dictionary struct {
+	name   string
+	ranks  map[string]int
+	maxLen int
+}
+
+var (
+	dictionariesOnce sync.Once
+	dictionaries     []dictionary
+)
+
+// rankedDictionaries returns the embedded dictionaries parsed once.
+func rankedDictionaries() []dictionary {
+	dictionariesOnce.Do(func() {
+		dictionaries = []dictionary{
+			newDictionary(DictionaryPasswords, strings.Fields(passwordsList)),
+			newDictionary(DictionaryEnglish, strings.Fields(englishList)),
+			newDictionary(DictionaryFemaleNames, strings.Fields(femaleNamesList)),
+			newDictionary(DictionaryMaleNames, strings.Fields(maleNamesList)),
+			newDictionary(DictionarySurnames, strings.Fields(surnamesList)),
+		}
+	})
+
+	return dictionaries
+}
+
+// newDictionary ranks the words by their order, a repeated word keeps its first rank.
+func newDictionary(name string, words []string) dictionary {
+	d := dictionary{name: name, ranks: make(map[string]int, len(words))}
+	for idx, word := range words {
+		word = strings.ToLower(word)
+		if _, ok := d.ranks[word]; ok || word == "" {
+			continue
+		}
+
+		d.ranks[word] = idx + 1
+		if l := utf8.RuneCountInString(word); l > d.maxLen {
+			d.maxLen = l
+		}
+	}
+
+	return d
+}
+
+// userDictionary ranks the inputs and their words such as a title, a username or a URL of the password.
+func userDictionary(inputs []string) dictionary {
+	var words []string
+	for _, input := range inputs {
+		words = append(words, input)
+		words = append(words, strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+
+	return newDictionary(DictionaryUserInputs, words)
+}
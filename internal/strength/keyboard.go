@@ -0,0 +1,80 @@
+package strength
+
+import "strings"
+
+// Names of the keyboard graphs.
+const (
+	GraphQwerty = "qwerty"
+	GraphKeypad = "keypad"
+)
+
+// qwertyLayout are the rows of a US keyboard, a key is the character and its shifted character.
+var qwertyLayout = []string{
+	"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+",
+	"qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|",
+	"aA sS dD fF gG hH jJ kK lL ;: '\"",
+	"zZ xX cC vV bB nN mM ,< .> /?",
+}
+
+var keypadLayout = []string{
+	"/ * -",
+	"7 8 9 +",
+	"4 5 6",
+	"1 2 3",
+	"0 .",
+}
+
+// Neighbour offsets of a key in the order of directions, keys of slanted keyboards are shifted
+// half a key right on every row, keys of aligned keypads are in a grid.
+var (
+	slantedDirections = [][2]int{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+	alignedDirections = [][2]int{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+)
+
+// graph maps characters to the keys next to them by direction, an empty key is no neighbour.
+type graph struct {
+	name              string
+	adjacent          map[rune][]string
+	shifted           map[rune]bool
+	startingPositions float64
+	averageDegree     float64
+}
+
+var graphs = []graph{
+	newGraph(GraphQwerty, qwertyLayout, []int{0, 1, 1, 1}, slantedDirections),
+	newGraph(GraphKeypad, keypadLayout, []int{1, 0, 0, 0, 1}, alignedDirections),
+}
+
+// newGraph builds the graph of the rows of keys, offsets are the positions of the first keys of the rows.
+func newGraph(name string, rows []string, offsets []int, directions [][2]int) graph {
+	positions := make(map[[2]int]string)
+	for y, row := range rows {
+		for x, key := range strings.Fields(row) {
+			positions[[2]int{offsets[y] + x, y}] = key
+		}
+	}
+
+	g := graph{name: name, adjacent: make(map[rune][]string), shifted: make(map[rune]bool)}
+	degrees := 0
+	for pos, key := range positions {
+		neighbours := make([]string, len(directions))
+		degree := 0
+		for idx, dir := range directions {
+			if neighbour, ok := positions[[2]int{pos[0] + dir[0], pos[1] + dir[1]}]; ok {
+				neighbours[idx] = neighbour
+				degree++
+			}
+		}
+
+		for idx, char := range []rune(key) {
+			g.adjacent[char] = neighbours
+			g.shifted[char] = idx > 0
+			degrees += degree
+		}
+	}
+
+	g.startingPositions = float64(len(g.adjacent))
+	g.averageDegree = float64(degrees) / g.startingPositions
+
+	return g
+}
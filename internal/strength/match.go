@@ -0,0 +1,456 @@
+package strength
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// l33tTable are the characters commonly substituted for letters.
+var l33tTable = map[rune]string{
+	'a': "4@",
+	'b': "8",
+	'c': "({[<",
+	'e': "3",
+	'g': "69",
+	'i': "1!|",
+	'l': "1|7",
+	'o': "0",
+	's': "$5",
+	't': "+7",
+	'x': "%",
+	'z': "2",
+}
+
+// match returns the matches of all patterns in the password.
+func match(password []rune, dicts []dictionary) []Match {
+	var matches []Match
+	matches = append(matches, dictionaryMatch(password, dicts)...)
+	matches = append(matches, reverseDictionaryMatch(password, dicts)...)
+	matches = append(matches, l33tMatch(password, dicts)...)
+	matches = append(matches, spatialMatch(password)...)
+	matches = append(matches, repeatMatch(password, dicts)...)
+	matches = append(matches, sequenceMatch(password)...)
+	matches = append(matches, yearMatch(password)...)
+
+	return matches
+}
+
+func dictionaryMatch(password []rune, dicts []dictionary) []Match {
+	lower := toLower(password)
+
+	var matches []Match
+	for _, dict := range dicts {
+		for i := range lower {
+			for j := i; j < len(lower) && j-i < dict.maxLen; j++ {
+				rank, ok := dict.ranks[string(lower[i:j+1])]
+				if !ok {
+					continue
+				}
+
+				token := password[i : j+1]
+				matches = append(matches, Match{
+					Pattern:    PatternDictionary,
+					I:          i,
+					J:          j,
+					Token:      string(token),
+					Dictionary: dict.name,
+					Rank:       rank,
+					Guesses:    float64(rank) * uppercaseVariations(token),
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+// reverseDictionaryMatch matches words written backwards.
+func reverseDictionaryMatch(password []rune, dicts []dictionary) []Match {
+	n := len(password)
+	matches := dictionaryMatch(reverse(password), dicts)
+	for idx := range matches {
+		m := &matches[idx]
+		m.I, m.J = n-1-m.J, n-1-m.I
+		m.Token = string(password[m.I : m.J+1])
+		m.Reversed = true
+		m.Guesses *= 2
+	}
+
+	return matches
+}
+
+// l33tMatch matches words with letters replaced by similar characters such as p4ssw0rd.
+func l33tMatch(password []rune, dicts []dictionary) []Match {
+	var matches []Match
+	for _, sub := range l33tSubs(password) {
+		subbed := make([]rune, len(password))
+		for idx, char := range password {
+			if letter, ok := sub[char]; ok {
+				char = letter
+			}
+
+			subbed[idx] = char
+		}
+
+		for _, m := range dictionaryMatch(subbed, dicts) {
+			token := password[m.I : m.J+1]
+			used := make(map[rune]rune)
+			for _, char := range token {
+				if letter, ok := sub[char]; ok {
+					used[char] = letter
+				}
+			}
+
+			// a single character is not a word and a word without substitutions is matched already
+			if len(token) <= 1 || len(used) == 0 {
+				continue
+			}
+
+			m.Token = string(token)
+			m.L33t = true
+			m.Guesses *= l33tVariations(token, used)
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// l33tSubs returns every way to replace the l33t characters of the password by letters.
+func l33tSubs(password []rune) []map[rune]rune {
+	letters := make(map[rune][]rune)
+	var chars []rune
+	for letter, subs := range l33tTable {
+		for _, char := range subs {
+			if !strings.ContainsRune(string(password), char) {
+				continue
+			}
+
+			if _, ok := letters[char]; !ok {
+				chars = append(chars, char)
+			}
+
+			letters[char] = append(letters[char], letter)
+		}
+	}
+
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	subs := []map[rune]rune{{}}
+	for _, char := range chars {
+		next := make([]map[rune]rune, 0, len(subs)*len(letters[char]))
+		for _, sub := range subs {
+			for _, letter := range letters[char] {
+				s := make(map[rune]rune, len(sub)+1)
+				for k, v := range sub {
+					s[k] = v
+				}
+
+				s[char] = letter
+				next = append(next, s)
+			}
+		}
+
+		subs = next
+	}
+
+	if len(chars) == 0 {
+		return nil
+	}
+
+	return subs
+}
+
+// spatialMatch matches runs of at least 3 adjacent keys such as qwerty or 7896.
+func spatialMatch(password []rune) []Match {
+	var matches []Match
+	for _, g := range graphs {
+		i := 0
+		for i < len(password)-1 {
+			j, turns, shifted, lastDirection := i+1, 0, 0, -1
+			if g.shifted[password[i]] {
+				shifted++
+			}
+
+			for j < len(password) {
+				direction, key := -1, ""
+				for idx, neighbour := range g.adjacent[password[j-1]] {
+					if neighbour != "" && strings.ContainsRune(neighbour, password[j]) {
+						direction, key = idx, neighbour
+						break
+					}
+				}
+
+				if direction < 0 {
+					break
+				}
+
+				if strings.IndexRune(key, password[j]) > 0 {
+					shifted++
+				}
+
+				if direction != lastDirection {
+					turns++
+					lastDirection = direction
+				}
+
+				j++
+			}
+
+			if j-i > 2 {
+				matches = append(matches, Match{
+					Pattern: PatternSpatial,
+					I:       i,
+					J:       j - 1,
+					Token:   string(password[i:j]),
+					Graph:   g.name,
+					Turns:   turns,
+					Shifted: shifted,
+					Guesses: spatialGuesses(g, j-i, turns, shifted),
+				})
+			}
+
+			i = j
+		}
+	}
+
+	return matches
+}
+
+// spatialGuesses counts the patterns of the length with up to turns direction changes from every key.
+func spatialGuesses(g graph, length, turns, shifted int) float64 {
+	var guesses float64
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= min(turns, i-1); j++ {
+			guesses += nCk(i-1, j-1) * g.startingPositions * math.Pow(g.averageDegree, float64(j))
+		}
+	}
+
+	if shifted > 0 {
+		unshifted := length - shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			var variations float64
+			for i := 1; i <= min(shifted, unshifted); i++ {
+				variations += nCk(shifted+unshifted, i)
+			}
+
+			guesses *= variations
+		}
+	}
+
+	return guesses
+}
+
+// repeatMatch matches a character or a string repeated at least twice such as aaa or abcabc.
+func repeatMatch(password []rune, dicts []dictionary) []Match {
+	var matches []Match
+	i := 0
+	for i < len(password) {
+		bestBase, bestCount := 0, 0
+		for base := 1; i+2*base <= len(password); base++ {
+			count := 1
+			for i+(count+1)*base <= len(password) &&
+				string(password[i+count*base:i+(count+1)*base]) == string(password[i:i+base]) {
+				count++
+			}
+
+			if count >= 2 && base*count > bestBase*bestCount {
+				bestBase, bestCount = base, count
+			}
+		}
+
+		if bestCount == 0 {
+			i++
+			continue
+		}
+
+		j := i + bestBase*bestCount - 1
+		base := password[i : i+bestBase]
+		baseGuesses, _ := mostGuessable(base, match(base, dicts))
+		matches = append(matches, Match{
+			Pattern:     PatternRepeat,
+			I:           i,
+			J:           j,
+			Token:       string(password[i : j+1]),
+			BaseToken:   string(base),
+			RepeatCount: bestCount,
+			Guesses:     baseGuesses * float64(bestCount),
+		})
+
+		i = j + 1
+	}
+
+	return matches
+}
+
+// sequenceMatch matches runs of characters with a constant step such as abcd, 1357 or 9876.
+func sequenceMatch(password []rune) []Match {
+	if len(password) < 2 {
+		return nil
+	}
+
+	var matches []Match
+	add := func(i, j, delta int) {
+		if (j-i > 1 || abs(delta) == 1) && delta != 0 && abs(delta) <= maxSequenceDelta {
+			token := password[i : j+1]
+			matches = append(matches, Match{
+				Pattern: PatternSequence,
+				I:       i,
+				J:       j,
+				Token:   string(token),
+				Delta:   delta,
+				Guesses: sequenceGuesses(token, delta > 0),
+			})
+		}
+	}
+
+	i, lastDelta := 0, int(password[1]-password[0])
+	for k := 2; k < len(password); k++ {
+		delta := int(password[k] - password[k-1])
+		if delta == lastDelta {
+			continue
+		}
+
+		add(i, k-1, lastDelta)
+		i, lastDelta = k-1, delta
+	}
+
+	add(i, len(password)-1, lastDelta)
+
+	return matches
+}
+
+// sequenceGuesses counts the sequences of the length from the likely starts, descending ones are less common.
+func sequenceGuesses(token []rune, ascending bool) float64 {
+	var base float64
+	switch first := token[0]; {
+	case strings.ContainsRune("aAzZ019", first):
+		base = 4
+	case unicode.IsDigit(first):
+		base = 10
+	default:
+		base = 26
+	}
+
+	if !ascending {
+		base *= 2
+	}
+
+	return base * float64(len(token))
+}
+
+// yearMatch matches years from 1900 to 2099.
+func yearMatch(password []rune) []Match {
+	var matches []Match
+	for i := 0; i+4 <= len(password); i++ {
+		token := string(password[i : i+4])
+		if !strings.HasPrefix(token, "19") && !strings.HasPrefix(token, "20") || !isDigits(token) {
+			continue
+		}
+
+		year := int(token[0]-'0')*1000 + int(token[1]-'0')*100 + int(token[2]-'0')*10 + int(token[3]-'0')
+		matches = append(matches, Match{
+			Pattern: PatternYear,
+			I:       i,
+			J:       i + 3,
+			Token:   token,
+			Year:    year,
+			Guesses: math.Max(float64(abs(year-time.Now().Year())), minYearSpace),
+		})
+	}
+
+	return matches
+}
+
+// uppercaseVariations counts the ways to capitalize a word with the same number of upper case letters,
+// a capitalized first or last letter and all upper case are as common as each other.
+func uppercaseVariations(token []rune) float64 {
+	upper, lower := 0, 0
+	for _, char := range token {
+		switch {
+		case unicode.IsUpper(char):
+			upper++
+		case unicode.IsLower(char):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+
+	if lower == 0 || upper == 1 && (unicode.IsUpper(token[0]) || unicode.IsUpper(token[len(token)-1])) {
+		return 2
+	}
+
+	var variations float64
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += nCk(upper+lower, i)
+	}
+
+	return variations
+}
+
+// l33tVariations counts the ways to substitute the letters of a word with the same number of substitutions.
+func l33tVariations(token []rune, sub map[rune]rune) float64 {
+	lower := toLower(token)
+	variations := 1.0
+	for char, letter := range sub {
+		subbed, unsubbed := 0, 0
+		for _, c := range lower {
+			switch c {
+			case char:
+				subbed++
+			case letter:
+				unsubbed++
+			}
+		}
+
+		if subbed == 0 || unsubbed == 0 {
+			variations *= 2
+			continue
+		}
+
+		var v float64
+		for i := 1; i <= min(subbed, unsubbed); i++ {
+			v += nCk(subbed+unsubbed, i)
+		}
+
+		variations *= v
+	}
+
+	return variations
+}
+
+func toLower(s []rune) []rune {
+	lower := make([]rune, len(s))
+	for idx, char := range s {
+		lower[idx] = unicode.ToLower(char)
+	}
+
+	return lower
+}
+
+func reverse(s []rune) []rune {
+	reversed := make([]rune, len(s))
+	for idx, char := range s {
+		reversed[len(s)-1-idx] = char
+	}
+
+	return reversed
+}
+
+func isDigits(s string) bool {
+	for _, char := range s {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,294 @@
+// Package strength estimates the number of guesses needed to crack a password the way zxcvbn does.
+// A password is covered by the matches of common passwords, words, names, keyboard patterns, repeats,
+// sequences and years, the estimate is the number of guesses of the cheapest cover.
+package strength
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	bruteforceCardinality           = 10
+	minGuessesBeforeGrowingSequence = 10000
+	minSubmatchGuessesSingleChar    = 10
+	minSubmatchGuessesMultiChar     = 50
+	minYearSpace                    = 20
+	maxSequenceDelta                = 5
+
+	// MaxLength bounds the estimated part of a password, the rest only makes it stronger.
+	MaxLength = 128
+)
+
+// Patterns of matches.
+const (
+	PatternDictionary = "dictionary"
+	PatternSpatial    = "spatial"
+	PatternRepeat     = "repeat"
+	PatternSequence   = "sequence"
+	PatternYear       = "year"
+	PatternBruteforce = "bruteforce"
+)
+
+// Score thresholds of guesses, a password needing fewer guesses than the threshold of a score gets the score below.
+var scoreThresholds = []float64{1e3, 1e6, 1e8, 1e10}
+
+// Match is a part of a password matched by a pattern. I and J are the positions of the first and
+// the last character of Token, Guesses is the number of guesses needed to find Token alone.
+type Match struct {
+	Pattern string
+	I, J    int
+	Token   string
+	Guesses float64
+
+	// Dictionary and Rank of a dictionary match, Reversed words are written backwards,
+	// L33t words have letters replaced by similar characters.
+	Dictionary string
+	Rank       int
+	Reversed   bool
+	L33t       bool
+
+	// Graph of a spatial match with the number of direction changes and shifted characters.
+	Graph   string
+	Turns   int
+	Shifted int
+
+	// BaseToken of a repeat match repeated RepeatCount times.
+	BaseToken   string
+	RepeatCount int
+
+	// Delta between the characters of a sequence match.
+	Delta int
+
+	Year int
+}
+
+// Result is the estimated strength of a password.
+type Result struct {
+	Guesses float64
+	// Entropy is log2 of Guesses.
+	Entropy float64
+	// Score is 0 for too guessable, 1 for very guessable, 2 for somewhat guessable,
+	// 3 for safely unguessable and 4 for very unguessable passwords.
+	Score int
+	// Sequence is the cheapest cover of the password by matches.
+	Sequence []Match
+}
+
+// Patterns returns the patterns of Sequence other than brute force in order without duplicates.
+func (r Result) Patterns() []string {
+	var patterns []string
+	seen := make(map[string]bool)
+	for _, m := range r.Sequence {
+		if m.Pattern == PatternBruteforce || seen[m.Pattern] {
+			continue
+		}
+
+		seen[m.Pattern] = true
+		patterns = append(patterns, m.Pattern)
+	}
+
+	return patterns
+}
+
+// Estimate estimates the strength of the password, inputs such as a title or a username of the password
+// are matched as a dictionary. Only the first MaxLength characters are estimated.
+func Estimate(password string, inputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) > MaxLength {
+		runes = runes[:MaxLength]
+	}
+
+	dicts := rankedDictionaries()
+	if len(inputs) > 0 {
+		dicts = append(dicts[:len(dicts):len(dicts)], userDictionary(inputs))
+	}
+
+	guesses, sequence := mostGuessable(runes, match(runes, dicts))
+
+	return Result{
+		Guesses:  guesses,
+		Entropy:  math.Log2(guesses),
+		Score:    score(guesses),
+		Sequence: sequence,
+	}
+}
+
+func score(guesses float64) int {
+	// a little more than the threshold to not score passwords exactly at it higher by rounding
+	const delta = 5
+
+	for s, threshold := range scoreThresholds {
+		if guesses < threshold+delta {
+			return s
+		}
+	}
+
+	return len(scoreThresholds)
+}
+
+// mostGuessable returns the fewest guesses of a sequence of the matches and brute force covering the password
+// and the sequence. A sequence of l matches needs l! times the product of their guesses to try every order
+// and an additive penalty growing with l so that fewer matches are preferred.
+func mostGuessable(password []rune, matches []Match) (float64, []Match) {
+	n := len(password)
+	if n == 0 {
+		return 1, nil
+	}
+
+	byJ := make([][]Match, n)
+	for _, m := range matches {
+		byJ[m.J] = append(byJ[m.J], m)
+	}
+
+	for _, ms := range byJ {
+		sort.SliceStable(ms, func(i, j int) bool { return ms[i].I < ms[j].I })
+	}
+
+	// optimal[k][l] is the best sequence of l matches covering the password up to k
+	type step struct {
+		m  Match
+		pi float64
+		g  float64
+	}
+
+	optimal := make([]map[int]step, n)
+	for k := range optimal {
+		optimal[k] = make(map[int]step)
+	}
+
+	update := func(m Match, l int) {
+		k := m.J
+		pi := guesses(m, n)
+		if l > 1 {
+			pi *= optimal[m.I-1][l-1].pi
+		}
+
+		g := factorial(l)*pi + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+		for competingL, competing := range optimal[k] {
+			if competingL <= l && competing.g <= g {
+				return
+			}
+		}
+
+		optimal[k][l] = step{m: m, pi: pi, g: g}
+	}
+
+	bruteforceUpdate := func(k int) {
+		update(bruteforceMatch(password, 0, k), 1)
+		for i := 1; i <= k; i++ {
+			m := bruteforceMatch(password, i, k)
+			for l, last := range optimal[i-1] {
+				// adjacent brute force matches are one longer match
+				if last.m.Pattern == PatternBruteforce {
+					continue
+				}
+
+				update(m, l+1)
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range byJ[k] {
+			if m.I == 0 {
+				update(m, 1)
+				continue
+			}
+
+			for l := range optimal[m.I-1] {
+				update(m, l+1)
+			}
+		}
+
+		bruteforceUpdate(k)
+	}
+
+	bestL, best := 0, math.Inf(1)
+	for l, s := range optimal[n-1] {
+		if s.g < best || s.g == best && l < bestL {
+			bestL, best = l, s.g
+		}
+	}
+
+	sequence := make([]Match, bestL)
+	for k, l := n-1, bestL; k >= 0; l-- {
+		m := optimal[k][l].m
+		m.Guesses = guesses(m, n)
+		sequence[l-1] = m
+		k = m.I - 1
+	}
+
+	return best, sequence
+}
+
+func bruteforceMatch(password []rune, i, j int) Match {
+	return Match{
+		Pattern: PatternBruteforce,
+		I:       i,
+		J:       j,
+		Token:   string(password[i : j+1]),
+		Guesses: math.Min(math.Pow(bruteforceCardinality, float64(j-i+1)), math.MaxFloat64),
+	}
+}
+
+// guesses returns the guesses of the match in a password of length n, a part of a password
+// needs at least a few guesses whatever the pattern.
+func guesses(m Match, n int) float64 {
+	length := m.J - m.I + 1
+	minGuesses := 1.0
+	if length < n {
+		minGuesses = minSubmatchGuessesMultiChar
+		if length == 1 {
+			minGuesses = minSubmatchGuessesSingleChar
+		}
+	}
+
+	if m.Pattern == PatternBruteforce {
+		minGuesses = minSubmatchGuessesMultiChar + 1
+		if length == 1 {
+			minGuesses = minSubmatchGuessesSingleChar + 1
+		}
+	}
+
+	return math.Max(m.Guesses, minGuesses)
+}
+
+func nCk(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r = r * float64(n) / float64(d)
+		n--
+	}
+
+	return r
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+
+	return f
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
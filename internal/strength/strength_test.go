@@ -0,0 +1,142 @@
+package strength
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEstimate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		password string
+		inputs   []string
+		score    int
+		patterns []string
+	}{
+		{
+			name:     "test_estimate_empty",
+			password: "",
+			score:    0,
+		},
+		{
+			name:     "test_estimate_common_password",
+			password: "password",
+			score:    0,
+			patterns: []string{PatternDictionary},
+		},
+		{
+			name:     "test_estimate_l33t",
+			password: "P@ssw0rd",
+			score:    0,
+			patterns: []string{PatternDictionary},
+		},
+		{
+			name:     "test_estimate_reversed",
+			password: "drowssap",
+			score:    0,
+			patterns: []string{PatternDictionary},
+		},
+		{
+			name:     "test_estimate_keyboard",
+			password: "zxcvfr5",
+			score:    1,
+			patterns: []string{PatternSpatial},
+		},
+		{
+			name:     "test_estimate_sequence",
+			password: "abcdefg",
+			score:    0,
+			patterns: []string{PatternSequence},
+		},
+		{
+			name:     "test_estimate_repeat",
+			password: "xyzxyzxyz",
+			score:    0,
+			patterns: []string{PatternRepeat},
+		},
+		{
+			name:     "test_estimate_year",
+			password: "1987",
+			score:    0,
+			patterns: []string{PatternYear},
+		},
+		{
+			name:     "test_estimate_user_input",
+			password: "Mybank",
+			inputs:   []string{"work/mybank", "bob@mybank.com"},
+			score:    0,
+			patterns: []string{PatternDictionary},
+		},
+		{
+			name:     "test_estimate_words",
+			password: "correcthorsebatterystaple",
+			score:    4,
+			patterns: []string{PatternDictionary},
+		},
+		{
+			name:     "test_estimate_random",
+			password: "ZYl9&U#0){3c6cS&T4;N",
+			score:    4,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Estimate(tc.password, tc.inputs...)
+			if diff := cmp.Diff(tc.score, result.Score); diff != "" {
+				t.Errorf("score diff (+got, -want): %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.patterns, result.Patterns()); diff != "" {
+				t.Errorf("patterns diff (+got, -want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEstimate_Sequence(t *testing.T) {
+	t.Parallel()
+
+	result := Estimate("Tr0ub4dour&3")
+
+	var covered string
+	for _, m := range result.Sequence {
+		covered += m.Token
+	}
+
+	if diff := cmp.Diff("Tr0ub4dour&3", covered); diff != "" {
+		t.Errorf("diff (+got, -want): %s", diff)
+	}
+
+	if result.Score < 3 {
+		t.Errorf("score %d, want at least 3", result.Score)
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		guesses float64
+		score   int
+	}{
+		{guesses: 1, score: 0},
+		{guesses: 1e3, score: 0},
+		{guesses: 1e4, score: 1},
+		{guesses: 1e7, score: 2},
+		{guesses: 1e9, score: 3},
+		{guesses: 1e11, score: 4},
+	}
+
+	for _, tc := range testCases {
+		if diff := cmp.Diff(tc.score, score(tc.guesses)); diff != "" {
+			t.Errorf("guesses %g diff (+got, -want): %s", tc.guesses, diff)
+		}
+	}
+}
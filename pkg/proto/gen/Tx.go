@@ -104,8 +104,20 @@ func (rcv *Tx) Payload(obj *Entry) *Entry {
 	return nil
 }
 
+func (rcv *Tx) Fields() uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetUint64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *Tx) MutateFields(n uint64) bool {
+	return rcv._tab.MutateUint64Slot(12, n)
+}
+
 func TxStart(builder *flatbuffers.Builder) {
-	builder.StartObject(4)
+	builder.StartObject(5)
 }
 func TxAddHash(builder *flatbuffers.Builder, hash flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(hash), 0)
@@ -122,6 +134,9 @@ func TxAddTs(builder *flatbuffers.Builder, ts int64) {
 func TxAddPayload(builder *flatbuffers.Builder, payload flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(payload), 0)
 }
+func TxAddFields(builder *flatbuffers.Builder, fields uint64) {
+	builder.PrependUint64Slot(4, fields, 0)
+}
 func TxEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }